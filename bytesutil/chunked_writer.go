@@ -0,0 +1,199 @@
+package bytesutil
+
+import (
+	"cmp"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/pierrre/go-libs/syncutil"
+)
+
+const chunkPoolSizeDefault = 1 << 14 // 16 KiB
+
+// ChunkPool is a pool of fixed-size chunks used by [ChunkedWriter].
+type ChunkPool struct {
+	pool syncutil.ValuePool[[]byte]
+
+	// Size defines the size of the chunks.
+	// It must not be changed after the pool is used.
+	// 0 (default) means 16 KiB.
+	Size int
+}
+
+// Get returns an empty chunk from the pool.
+//
+// The returned chunk has a length of 0 and a capacity of Size.
+func (p *ChunkPool) Get() []byte {
+	b := p.pool.Get()
+	if b == nil {
+		return make([]byte, 0, p.size())
+	}
+	return b
+}
+
+// Put puts the chunk to the pool.
+//
+// If the chunk capacity is not Size, it's discarded.
+// WARNING: the caller MUST NOT reuse the chunk's content after this call.
+func (p *ChunkPool) Put(b []byte) {
+	if cap(b) == p.size() {
+		p.pool.Put(b[:0])
+	}
+}
+
+func (p *ChunkPool) size() int {
+	return cmp.Or(p.Size, chunkPoolSizeDefault)
+}
+
+var chunkPoolDefault = &ChunkPool{}
+
+// ChunkedWriter is a writer that stores data in a list of fixed-size chunks.
+//
+// Unlike [Writer], it never copies the existing data when it grows.
+// Chunks are taken from Pool and returned to it by [ChunkedWriter.Reset].
+//
+// The zero value is ready to use.
+type ChunkedWriter struct {
+	// Pool is the pool of chunks.
+	// nil (default) means a shared pool of 16 KiB chunks.
+	Pool *ChunkPool
+
+	chunks [][]byte
+	len    int
+}
+
+// Write appends p to the writer.
+//
+// Write always returns len(p), nil.
+func (w *ChunkedWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := w.available()
+		m := min(len(p), cap(*c)-len(*c))
+		*c = append(*c, p[:m]...)
+		p = p[m:]
+		n += m
+	}
+	w.len += n
+	return n, nil
+}
+
+// WriteString appends s to the writer.
+//
+// WriteString always returns len(s), nil.
+func (w *ChunkedWriter) WriteString(s string) (n int, err error) {
+	for len(s) > 0 {
+		c := w.available()
+		m := min(len(s), cap(*c)-len(*c))
+		*c = append(*c, s[:m]...)
+		s = s[m:]
+		n += m
+	}
+	w.len += n
+	return n, nil
+}
+
+// WriteByte appends c to the writer.
+//
+// WriteByte always returns nil.
+func (w *ChunkedWriter) WriteByte(c byte) error {
+	ch := w.available()
+	*ch = append(*ch, c)
+	w.len++
+	return nil
+}
+
+// available returns the last chunk, with at least 1 byte of available capacity.
+func (w *ChunkedWriter) available() *[]byte {
+	if len(w.chunks) > 0 {
+		c := &w.chunks[len(w.chunks)-1]
+		if len(*c) < cap(*c) {
+			return c
+		}
+	}
+	w.chunks = append(w.chunks, w.pool().Get())
+	return &w.chunks[len(w.chunks)-1]
+}
+
+func (w *ChunkedWriter) pool() *ChunkPool {
+	if w.Pool != nil {
+		return w.Pool
+	}
+	return chunkPoolDefault
+}
+
+// WriteTo writes the contents of the writer to wr.
+//
+// The contents of the writer are not modified.
+// If wr is a [net.Conn], it uses vectored I/O (see [net.Buffers]).
+func (w *ChunkedWriter) WriteTo(wr io.Writer) (n int64, err error) {
+	if _, ok := wr.(net.Conn); ok {
+		bufs := w.Buffers()
+		return bufs.WriteTo(wr) //nolint:wrapcheck // Not needed.
+	}
+	for _, c := range w.chunks {
+		m, err := wr.Write(c)
+		n += int64(m)
+		if err != nil {
+			return n, err //nolint:wrapcheck // Not needed.
+		}
+		if m != len(c) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// Buffers returns the chunks of the writer as [net.Buffers].
+//
+// The data is not copied: the writer must not be modified while the returned value is used.
+// The returned value can be consumed (e.g. with [net.Buffers.WriteTo]) without modifying the writer.
+func (w *ChunkedWriter) Buffers() net.Buffers {
+	return w.AppendBuffers(make(net.Buffers, 0, len(w.chunks)))
+}
+
+// AppendBuffers appends the chunks of the writer to bufs.
+//
+// It allows to reuse the [net.Buffers] slice.
+// See [ChunkedWriter.Buffers].
+func (w *ChunkedWriter) AppendBuffers(bufs net.Buffers) net.Buffers {
+	return append(bufs, w.chunks...)
+}
+
+// AppendTo appends the contents of the writer to dst.
+func (w *ChunkedWriter) AppendTo(dst []byte) []byte {
+	dst = slices.Grow(dst, w.len)
+	for _, c := range w.chunks {
+		dst = append(dst, c...)
+	}
+	return dst
+}
+
+// Len returns the number of bytes currently stored in the writer.
+func (w *ChunkedWriter) Len() int {
+	return w.len
+}
+
+// String returns the contents of the writer as a string.
+func (w *ChunkedWriter) String() string {
+	var sb strings.Builder
+	sb.Grow(w.len)
+	for _, c := range w.chunks {
+		sb.Write(c)
+	}
+	return sb.String()
+}
+
+// Reset resets the writer to be empty, and puts the chunks back to the pool.
+//
+// WARNING: the caller MUST NOT reuse the writer's content after this call.
+func (w *ChunkedWriter) Reset() {
+	p := w.pool()
+	for i, c := range w.chunks {
+		p.Put(c)
+		w.chunks[i] = nil
+	}
+	w.chunks = w.chunks[:0]
+	w.len = 0
+}
@@ -0,0 +1,204 @@
+package bytesutil_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func ExampleChunkedWriter() {
+	var w ChunkedWriter
+	defer w.Reset()
+	_, _ = w.WriteString("a")
+	_, _ = w.Write([]byte("b"))
+	_ = w.WriteByte('c')
+	fmt.Println(w.String())
+	// Output: abc
+}
+
+func newTestChunkedWriter() *ChunkedWriter {
+	return &ChunkedWriter{
+		Pool: &ChunkPool{
+			Size: 4,
+		},
+	}
+}
+
+func TestChunkedWriterWrite(t *testing.T) {
+	w := newTestChunkedWriter()
+	n, err := w.Write([]byte("abcdefghij"))
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10)
+	assert.Equal(t, w.Len(), 10)
+	assert.Equal(t, w.String(), "abcdefghij")
+	bufs := w.Buffers()
+	assert.SliceLen(t, bufs, 3)
+	assert.BytesEqual(t, bufs[0], []byte("abcd"))
+	assert.BytesEqual(t, bufs[2], []byte("ij"))
+}
+
+func BenchmarkChunkedWriterWrite(b *testing.B) {
+	var w ChunkedWriter
+	p := []byte(testWriterPoolData)
+	for b.Loop() {
+		_, _ = w.Write(p)
+		w.Reset()
+	}
+}
+
+func TestChunkedWriterWriteString(t *testing.T) {
+	w := newTestChunkedWriter()
+	n, err := w.WriteString("abcdefghij")
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10)
+	assert.Equal(t, w.String(), "abcdefghij")
+}
+
+func BenchmarkChunkedWriterWriteString(b *testing.B) {
+	var w ChunkedWriter
+	for b.Loop() {
+		_, _ = w.WriteString(testWriterPoolData)
+		w.Reset()
+	}
+}
+
+func TestChunkedWriterWriteByte(t *testing.T) {
+	w := newTestChunkedWriter()
+	for _, c := range []byte("abcdef") {
+		err := w.WriteByte(c)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, w.Len(), 6)
+	assert.Equal(t, w.String(), "abcdef")
+	assert.SliceLen(t, w.Buffers(), 2)
+}
+
+func BenchmarkChunkedWriterWriteByte(b *testing.B) {
+	var w ChunkedWriter
+	for b.Loop() {
+		_ = w.WriteByte('a')
+		w.Reset()
+	}
+}
+
+func TestChunkedWriterWriteTo(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	var dst Writer
+	n, err := w.WriteTo(&dst)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10)
+	assert.Equal(t, dst.String(), "abcdefghij")
+	assert.Equal(t, w.Len(), 10)
+}
+
+func TestChunkedWriterWriteToError(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	n, err := w.WriteTo(writerFunc(func(p []byte) (int, error) {
+		return 1, errors.New("error")
+	}))
+	assert.Error(t, err)
+	assert.Equal(t, n, 1)
+}
+
+func TestChunkedWriterWriteToShortWrite(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	_, err := w.WriteTo(writerFunc(func(p []byte) (int, error) {
+		return 1, nil
+	}))
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+func TestChunkedWriterWriteToConn(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	c1, c2 := net.Pipe()
+	defer c1.Close() //nolint:errcheck // Not needed.
+	defer c2.Close() //nolint:errcheck // Not needed.
+	go func() {
+		_, _ = w.WriteTo(c1)
+		_ = c1.Close()
+	}()
+	b, err := io.ReadAll(c2)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), "abcdefghij")
+	assert.Equal(t, w.String(), "abcdefghij")
+}
+
+func BenchmarkChunkedWriterWriteTo(b *testing.B) {
+	var w ChunkedWriter
+	_, _ = w.WriteString(testWriterPoolData)
+	for b.Loop() {
+		_, _ = w.WriteTo(io.Discard)
+	}
+}
+
+func TestChunkedWriterBuffersConsume(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	bufs := w.Buffers()
+	_, err := bufs.WriteTo(io.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, w.String(), "abcdefghij")
+}
+
+func TestChunkedWriterAppendBuffers(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	bufs := w.AppendBuffers(net.Buffers{[]byte("0")})
+	assert.SliceLen(t, bufs, 4)
+}
+
+func TestChunkedWriterAppendTo(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	b := w.AppendTo([]byte("0"))
+	assert.BytesEqual(t, b, []byte("0abcdefghij"))
+}
+
+func TestChunkedWriterReset(t *testing.T) {
+	w := newTestChunkedWriter()
+	_, _ = w.WriteString("abcdefghij")
+	w.Reset()
+	assert.Equal(t, w.Len(), 0)
+	assert.SliceEmpty(t, w.Buffers())
+	_, _ = w.WriteString("klm")
+	assert.Equal(t, w.String(), "klm")
+}
+
+func TestChunkedWriterLarge(t *testing.T) {
+	var w ChunkedWriter
+	defer w.Reset()
+	s := strings.Repeat(testWriterPoolData, 1000)
+	_, _ = w.WriteString(s)
+	assert.Equal(t, w.Len(), len(s))
+	assert.Equal(t, w.String(), s)
+}
+
+func TestChunkedWriterAllocs(t *testing.T) {
+	var w ChunkedWriter
+	_, _ = w.WriteString(testWriterPoolData)
+	w.Reset()
+	assert.AllocsPerRun(t, 100, func() {
+		_, _ = w.WriteString(testWriterPoolData)
+		w.Reset()
+	}, 0)
+}
+
+func TestChunkPoolPutDiscard(t *testing.T) {
+	p := &ChunkPool{
+		Size: 4,
+	}
+	p.Put(make([]byte, 0, 8))
+	b := p.Get()
+	assert.Equal(t, cap(b), 4)
+	assert.SliceEmpty(t, b)
+}
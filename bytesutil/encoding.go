@@ -0,0 +1,188 @@
+package bytesutil
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+)
+
+// AppendHex appends the hexadecimal encoding of src to the writer.
+func (w *Writer) AppendHex(src []byte) {
+	*w = hex.AppendEncode(*w, src)
+}
+
+// AppendBase64 appends the base64 encoding of src to the writer, with the given [base64.Encoding].
+func (w *Writer) AppendBase64(enc *base64.Encoding, src []byte) {
+	*w = enc.AppendEncode(*w, src)
+}
+
+// AppendBase32 appends the base32 encoding of src to the writer, with the given [base32.Encoding].
+func (w *Writer) AppendBase32(enc *base32.Encoding, src []byte) {
+	*w = enc.AppendEncode(*w, src)
+}
+
+// WriteHex writes the hexadecimal encoding of src to w.
+//
+// It returns the number of encoded bytes written to w.
+func WriteHex(w io.Writer, src []byte) (int, error) {
+	return writeEncode(w, hexEncoder, src)
+}
+
+// WriteBase64 writes the base64 encoding of src to w, with the given [base64.Encoding].
+//
+// It returns the number of encoded bytes written to w.
+func WriteBase64(w io.Writer, enc *base64.Encoding, src []byte) (int, error) {
+	return writeEncode(w, newBase64Encoder(enc), src)
+}
+
+// WriteBase32 writes the base32 encoding of src to w, with the given [base32.Encoding].
+//
+// It returns the number of encoded bytes written to w.
+func WriteBase32(w io.Writer, enc *base32.Encoding, src []byte) (int, error) {
+	return writeEncode(w, newBase32Encoder(enc), src)
+}
+
+func writeEncode(w io.Writer, enc encoder, src []byte) (int, error) {
+	var n int
+	for len(src) > 0 {
+		m, err := enc.writeBlocks(w, src)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		src = src[min(len(src), encodeChunkBlocks*enc.blockSize):]
+	}
+	return n, nil
+}
+
+// EncodeWriter is an [io.WriteCloser] that encodes the written data and writes it to an [io.Writer].
+//
+// The data is encoded with a pooled buffer.
+// The last partial block is kept until the next write or [EncodeWriter.Close].
+//
+// It must be created with [NewHexWriter], [NewBase64Writer] or [NewBase32Writer].
+type EncodeWriter struct {
+	w       io.Writer
+	enc     encoder
+	partial [encodeMaxBlockSize]byte
+	npart   int
+}
+
+// NewHexWriter returns a new [EncodeWriter] that writes the hexadecimal encoding to w.
+func NewHexWriter(w io.Writer) *EncodeWriter {
+	return &EncodeWriter{
+		w:   w,
+		enc: hexEncoder,
+	}
+}
+
+// NewBase64Writer returns a new [EncodeWriter] that writes the base64 encoding to w, with the given [base64.Encoding].
+func NewBase64Writer(w io.Writer, enc *base64.Encoding) *EncodeWriter {
+	return &EncodeWriter{
+		w:   w,
+		enc: newBase64Encoder(enc),
+	}
+}
+
+// NewBase32Writer returns a new [EncodeWriter] that writes the base32 encoding to w, with the given [base32.Encoding].
+func NewBase32Writer(w io.Writer, enc *base32.Encoding) *EncodeWriter {
+	return &EncodeWriter{
+		w:   w,
+		enc: newBase32Encoder(enc),
+	}
+}
+
+// Write implements [io.Writer].
+func (e *EncodeWriter) Write(p []byte) (n int, err error) {
+	bs := e.enc.blockSize
+	if e.npart > 0 {
+		m := copy(e.partial[e.npart:bs], p)
+		e.npart += m
+		n += m
+		p = p[m:]
+		if e.npart < bs {
+			return n, nil
+		}
+		_, err = e.enc.writeBlocks(e.w, e.partial[:bs])
+		if err != nil {
+			return n, err
+		}
+		e.npart = 0
+	}
+	for len(p) >= bs {
+		m := min(len(p)-len(p)%bs, encodeChunkBlocks*bs)
+		_, err = e.enc.writeBlocks(e.w, p[:m])
+		if err != nil {
+			return n, err
+		}
+		n += m
+		p = p[m:]
+	}
+	e.npart = copy(e.partial[:], p)
+	n += e.npart
+	return n, nil
+}
+
+// Close writes the last partial block, with padding if the encoding uses it.
+//
+// It doesn't close the underlying [io.Writer].
+func (e *EncodeWriter) Close() error {
+	if e.npart == 0 {
+		return nil
+	}
+	_, err := e.enc.writeBlocks(e.w, e.partial[:e.npart])
+	e.npart = 0
+	return err
+}
+
+const (
+	encodeMaxBlockSize = 5
+	encodeChunkBlocks  = 1 << 10
+)
+
+type encoder struct {
+	appendEncode func(dst, src []byte) []byte
+	encodedLen   func(n int) int
+	blockSize    int
+}
+
+var hexEncoder = encoder{
+	appendEncode: hex.AppendEncode,
+	encodedLen:   hex.EncodedLen,
+	blockSize:    1,
+}
+
+func newBase64Encoder(enc *base64.Encoding) encoder {
+	return encoder{
+		appendEncode: enc.AppendEncode,
+		encodedLen:   enc.EncodedLen,
+		blockSize:    3,
+	}
+}
+
+func newBase32Encoder(enc *base32.Encoding) encoder {
+	return encoder{
+		appendEncode: enc.AppendEncode,
+		encodedLen:   enc.EncodedLen,
+		blockSize:    5,
+	}
+}
+
+// writeBlocks encodes at most encodeChunkBlocks blocks of src and writes them to w.
+func (enc encoder) writeBlocks(w io.Writer, src []byte) (int, error) {
+	src = src[:min(len(src), encodeChunkBlocks*enc.blockSize)]
+	bw := encodeWriterPool.GetSize(enc.encodedLen(len(src)))
+	defer encodeWriterPool.Put(bw)
+	*bw = enc.appendEncode(*bw, src)
+	n, err := w.Write(*bw)
+	if err != nil {
+		return n, err //nolint:wrapcheck // Not needed.
+	}
+	if n != len(*bw) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+var encodeWriterPool = &WriterPool{}
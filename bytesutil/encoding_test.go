@@ -0,0 +1,206 @@
+package bytesutil_test
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func ExampleWriter_AppendHex() {
+	var w Writer
+	w.AppendString("id=")
+	w.AppendHex([]byte{0xde, 0xad, 0xbe, 0xef})
+	fmt.Println(w.String())
+	// Output: id=deadbeef
+}
+
+var testEncodingData = bytes.Repeat([]byte(testWriterPoolData), 20)
+
+type testEncoding struct {
+	name     string
+	encode   func(src []byte) string
+	append   func(w *Writer, src []byte)
+	write    func(w io.Writer, src []byte) (int, error)
+	newWrite func(w io.Writer) *EncodeWriter
+}
+
+var testEncodings = []testEncoding{
+	{
+		name:   "Hex",
+		encode: hex.EncodeToString,
+		append: (*Writer).AppendHex,
+		write:  WriteHex,
+		newWrite: func(w io.Writer) *EncodeWriter {
+			return NewHexWriter(w)
+		},
+	},
+	newTestBase64Encoding("Base64Std", base64.StdEncoding),
+	newTestBase64Encoding("Base64RawStd", base64.RawStdEncoding),
+	newTestBase64Encoding("Base64URL", base64.URLEncoding),
+	newTestBase64Encoding("Base64RawURL", base64.RawURLEncoding),
+	newTestBase32Encoding("Base32Std", base32.StdEncoding),
+	newTestBase32Encoding("Base32Hex", base32.HexEncoding.WithPadding(base32.NoPadding)),
+}
+
+func newTestBase64Encoding(name string, enc *base64.Encoding) testEncoding {
+	return testEncoding{
+		name:   name,
+		encode: enc.EncodeToString,
+		append: func(w *Writer, src []byte) {
+			w.AppendBase64(enc, src)
+		},
+		write: func(w io.Writer, src []byte) (int, error) {
+			return WriteBase64(w, enc, src)
+		},
+		newWrite: func(w io.Writer) *EncodeWriter {
+			return NewBase64Writer(w, enc)
+		},
+	}
+}
+
+func newTestBase32Encoding(name string, enc *base32.Encoding) testEncoding {
+	return testEncoding{
+		name:   name,
+		encode: enc.EncodeToString,
+		append: func(w *Writer, src []byte) {
+			w.AppendBase32(enc, src)
+		},
+		write: func(w io.Writer, src []byte) (int, error) {
+			return WriteBase32(w, enc, src)
+		},
+		newWrite: func(w io.Writer) *EncodeWriter {
+			return NewBase32Writer(w, enc)
+		},
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	for _, enc := range testEncodings {
+		t.Run(enc.name, func(t *testing.T) {
+			for _, size := range []int{0, 1, 2, 3, 4, 5, 6, 7, 100, len(testEncodingData)} {
+				src := testEncodingData[:size]
+				expected := enc.encode(src)
+				t.Run(fmt.Sprint(size), func(t *testing.T) {
+					t.Run("Append", func(t *testing.T) {
+						w := Writer("0")
+						enc.append(&w, src)
+						assert.Equal(t, w.String(), "0"+expected)
+					})
+					t.Run("Write", func(t *testing.T) {
+						var w Writer
+						n, err := enc.write(&w, src)
+						assert.NoError(t, err)
+						assert.Equal(t, n, len(expected))
+						assert.Equal(t, w.String(), expected)
+					})
+					t.Run("EncodeWriter", func(t *testing.T) {
+						for _, chunk := range []int{1, 2, 4, 7, 1000} {
+							var w Writer
+							ew := enc.newWrite(&w)
+							for p := src; len(p) > 0; {
+								m := min(chunk, len(p))
+								n, err := ew.Write(p[:m])
+								assert.NoError(t, err)
+								assert.Equal(t, n, m)
+								p = p[m:]
+							}
+							err := ew.Close()
+							assert.NoError(t, err)
+							assert.Equal(t, w.String(), expected)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkWriterAppendHex(b *testing.B) {
+	var w Writer
+	src := []byte(testWriterPoolData)
+	for b.Loop() {
+		w.AppendHex(src)
+		w.Reset()
+	}
+}
+
+func BenchmarkWriterAppendBase64(b *testing.B) {
+	var w Writer
+	src := []byte(testWriterPoolData)
+	for b.Loop() {
+		w.AppendBase64(base64.StdEncoding, src)
+		w.Reset()
+	}
+}
+
+func BenchmarkWriteHex(b *testing.B) {
+	src := []byte(testWriterPoolData)
+	for b.Loop() {
+		_, _ = WriteHex(io.Discard, src)
+	}
+}
+
+func BenchmarkWriteBase64(b *testing.B) {
+	src := []byte(testWriterPoolData)
+	for b.Loop() {
+		_, _ = WriteBase64(io.Discard, base64.StdEncoding, src)
+	}
+}
+
+func BenchmarkEncodeWriterBase64(b *testing.B) {
+	src := []byte(testWriterPoolData)
+	ew := NewBase64Writer(io.Discard, base64.StdEncoding)
+	for b.Loop() {
+		_, _ = ew.Write(src)
+		_ = ew.Close()
+	}
+}
+
+func TestWriteHexAllocs(t *testing.T) {
+	src := []byte(testWriterPoolData)
+	_, _ = WriteHex(io.Discard, src)
+	assert.AllocsPerRun(t, 100, func() {
+		_, _ = WriteHex(io.Discard, src)
+	}, 0)
+}
+
+func TestWriteHexError(t *testing.T) {
+	w := writerFunc(func(p []byte) (int, error) {
+		return 0, errors.New("error")
+	})
+	_, err := WriteHex(w, []byte("abc"))
+	assert.Error(t, err)
+}
+
+func TestWriteHexShortWrite(t *testing.T) {
+	w := writerFunc(func(p []byte) (int, error) {
+		return 1, nil
+	})
+	_, err := WriteHex(w, []byte("abc"))
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+func TestEncodeWriterError(t *testing.T) {
+	w := writerFunc(func(p []byte) (int, error) {
+		return 0, errors.New("error")
+	})
+	ew := NewBase64Writer(w, base64.StdEncoding)
+	n, err := ew.Write([]byte("ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, n, 2)
+	n, err = ew.Write([]byte("cd"))
+	assert.Error(t, err)
+	assert.Equal(t, n, 1)
+	_, err = ew.Write([]byte("abcdef"))
+	assert.Error(t, err)
+	err = ew.Close()
+	assert.Error(t, err)
+}
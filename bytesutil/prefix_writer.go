@@ -0,0 +1,127 @@
+package bytesutil
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pierrre/go-libs/unsafeio"
+)
+
+// PrefixWriter is an [io.Writer] that inserts Prefix at the start of every line written to W.
+//
+// The prefix is inserted lazily, when the first byte of a line is written, so a trailing '\n' is not followed by a prefix.
+// Writing doesn't allocate.
+type PrefixWriter struct {
+	W      io.Writer
+	Prefix string
+
+	midLine bool
+}
+
+// NewIndentWriter returns a [PrefixWriter] that indents every line with n tabs.
+func NewIndentWriter(w io.Writer, n int) *PrefixWriter {
+	return &PrefixWriter{
+		W:      w,
+		Prefix: indent(n),
+	}
+}
+
+// Write implements [io.Writer].
+func (w *PrefixWriter) Write(p []byte) (n int, err error) {
+	return prefixWrite(w, p)
+}
+
+// WriteString implements [io.StringWriter].
+func (w *PrefixWriter) WriteString(s string) (n int, err error) {
+	return prefixWrite(w, s)
+}
+
+func prefixWrite[S string | []byte](w *PrefixWriter, p S) (n int, err error) {
+	for len(p) > 0 {
+		if !w.midLine && w.Prefix != "" {
+			_, err = unsafeio.WriteString(w.W, w.Prefix)
+			if err != nil {
+				return n, err //nolint:wrapcheck // Not needed.
+			}
+		}
+		w.midLine = true
+		i := indexLineEnd(p)
+		var m int
+		m, err = writeStringOrBytes(w.W, p[:i])
+		n += m
+		if err != nil {
+			return n, err //nolint:wrapcheck // Not needed.
+		}
+		if m != i {
+			return n, io.ErrShortWrite
+		}
+		if p[i-1] == '\n' {
+			w.midLine = false
+		}
+		p = p[i:]
+	}
+	return n, nil
+}
+
+func writeStringOrBytes[S string | []byte](w io.Writer, p S) (int, error) {
+	if b, ok := any(p).([]byte); ok {
+		return w.Write(b) //nolint:wrapcheck // Not needed.
+	}
+	return unsafeio.WriteString(w, string(p)) //nolint:wrapcheck // Not needed.
+}
+
+// Reset resets the [PrefixWriter] state, so the next written byte is considered as the start of a line.
+func (w *PrefixWriter) Reset() {
+	w.midLine = false
+}
+
+// AppendPrefix appends src to dst, inserting prefix at the start of every line.
+//
+// The prefix is not inserted after a trailing '\n'.
+func AppendPrefix(dst, src []byte, prefix string) []byte {
+	return appendPrefix(dst, src, prefix)
+}
+
+// AppendPrefixString is like [AppendPrefix] for a string.
+func AppendPrefixString(dst []byte, src, prefix string) []byte {
+	return appendPrefix(dst, src, prefix)
+}
+
+func appendPrefix[S string | []byte](dst []byte, src S, prefix string) []byte {
+	for len(src) > 0 {
+		i := indexLineEnd(src)
+		dst = append(dst, prefix...)
+		dst = append(dst, src[:i]...)
+		src = src[i:]
+	}
+	return dst
+}
+
+// AppendIndent appends src to dst, indenting every line with n tabs.
+func AppendIndent(dst, src []byte, n int) []byte {
+	return appendPrefix(dst, src, indent(n))
+}
+
+// AppendIndentString is like [AppendIndent] for a string.
+func AppendIndentString(dst []byte, src string, n int) []byte {
+	return appendPrefix(dst, src, indent(n))
+}
+
+// indexLineEnd returns the index after the end of the first line (including '\n'), or len(p).
+func indexLineEnd[S string | []byte](p S) int {
+	for i := range len(p) {
+		if p[i] == '\n' {
+			return i + 1
+		}
+	}
+	return len(p)
+}
+
+const indentTabs = "\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t"
+
+func indent(n int) string {
+	if n <= len(indentTabs) {
+		return indentTabs[:max(n, 0)]
+	}
+	return strings.Repeat("\t", n)
+}
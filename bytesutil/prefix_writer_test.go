@@ -0,0 +1,178 @@
+package bytesutil_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func ExamplePrefixWriter() {
+	w := &PrefixWriter{
+		W:      os.Stdout,
+		Prefix: "> ",
+	}
+	_, _ = w.WriteString("a\nb")
+	_, _ = w.WriteString("c\nd\n")
+	// Output:
+	// > a
+	// > bc
+	// > d
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out Writer
+	w := &PrefixWriter{
+		W:      &out,
+		Prefix: "> ",
+	}
+	for _, s := range []string{"a", "b\n", "c\nd", "", "\n", "\n"} {
+		n, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+		assert.Equal(t, n, len(s))
+	}
+	assert.Equal(t, out.String(), "> ab\n> c\n> d\n> \n")
+}
+
+func BenchmarkPrefixWriter(b *testing.B) {
+	w := &PrefixWriter{
+		W:      io.Discard,
+		Prefix: "> ",
+	}
+	p := []byte("abc\ndef\n")
+	for b.Loop() {
+		_, _ = w.Write(p)
+	}
+}
+
+func TestPrefixWriterWriteString(t *testing.T) {
+	var out Writer
+	w := &PrefixWriter{
+		W:      &out,
+		Prefix: "> ",
+	}
+	n, err := w.WriteString("a\nb\n")
+	assert.NoError(t, err)
+	assert.Equal(t, n, 4)
+	assert.Equal(t, out.String(), "> a\n> b\n")
+}
+
+func TestPrefixWriterAllocs(t *testing.T) {
+	var out Writer
+	w := &PrefixWriter{
+		W:      &out,
+		Prefix: "> ",
+	}
+	p := []byte("abc\ndef\n")
+	_, _ = w.Write(p)
+	assert.AllocsPerRun(t, 100, func() {
+		out.Reset()
+		_, _ = w.Write(p)
+		_, _ = w.WriteString("abc\ndef\n")
+	}, 0)
+}
+
+func TestPrefixWriterErrorPrefix(t *testing.T) {
+	w := &PrefixWriter{
+		W: writerFunc(func(p []byte) (int, error) {
+			return 0, errors.New("error")
+		}),
+		Prefix: "> ",
+	}
+	n, err := w.WriteString("abc")
+	assert.Error(t, err)
+	assert.Equal(t, n, 0)
+}
+
+func TestPrefixWriterErrorLine(t *testing.T) {
+	w := &PrefixWriter{
+		W: writerFunc(func(p []byte) (int, error) {
+			if string(p) == "b\n" {
+				return 1, errors.New("error")
+			}
+			return len(p), nil
+		}),
+		Prefix: "> ",
+	}
+	n, err := w.WriteString("a\nb\nc\n")
+	assert.Error(t, err)
+	assert.Equal(t, n, 3)
+}
+
+func TestPrefixWriterShortWrite(t *testing.T) {
+	w := &PrefixWriter{
+		W: writerFunc(func(p []byte) (int, error) {
+			return 0, nil
+		}),
+	}
+	_, err := w.WriteString("abc")
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+func TestPrefixWriterReset(t *testing.T) {
+	var out Writer
+	w := &PrefixWriter{
+		W:      &out,
+		Prefix: "> ",
+	}
+	_, _ = w.WriteString("a")
+	w.Reset()
+	_, _ = w.WriteString("b")
+	assert.Equal(t, out.String(), "> a> b")
+}
+
+func TestNewIndentWriter(t *testing.T) {
+	var out Writer
+	w := NewIndentWriter(&out, 2)
+	_, _ = w.WriteString("a\nb\n")
+	assert.Equal(t, out.String(), "\t\ta\n\t\tb\n")
+}
+
+func TestAppendPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		src      string
+		expected string
+	}{
+		{src: "", expected: "0"},
+		{src: "a", expected: "0> a"},
+		{src: "a\n", expected: "0> a\n"},
+		{src: "a\nb", expected: "0> a\n> b"},
+		{src: "a\n\nb\n", expected: "0> a\n> \n> b\n"},
+	} {
+		b := AppendPrefix([]byte("0"), []byte(tc.src), "> ")
+		assert.Equal(t, string(b), tc.expected)
+		b = AppendPrefixString([]byte("0"), tc.src, "> ")
+		assert.Equal(t, string(b), tc.expected)
+	}
+}
+
+func BenchmarkAppendPrefix(b *testing.B) {
+	src := []byte("abc\ndef\n")
+	var dst []byte
+	for b.Loop() {
+		dst = AppendPrefix(dst[:0], src, "> ")
+	}
+}
+
+func TestAppendIndent(t *testing.T) {
+	b := AppendIndent(nil, []byte("a\nb\n"), 1)
+	assert.Equal(t, string(b), "\ta\n\tb\n")
+	b = AppendIndentString(nil, "a\n", 20)
+	assert.Equal(t, string(b), "\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\t\ta\n")
+	b = AppendIndentString(nil, "a\n", -1)
+	assert.Equal(t, string(b), "a\n")
+}
+
+func ExampleAppendIndentString() {
+	b := []byte("error:\n")
+	b = AppendIndentString(b, "main.main\n\tmain.go:10\n", 1)
+	fmt.Print(string(b))
+	// Output:
+	// error:
+	// 	main.main
+	// 		main.go:10
+}
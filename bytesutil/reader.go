@@ -0,0 +1,127 @@
+package bytesutil
+
+import (
+	"errors"
+	"io"
+)
+
+// Reader implements [io.Reader], [io.ReaderAt], [io.Seeker], [io.ByteScanner] and [io.WriterTo] by reading from a []byte.
+//
+// It is similar to [bytes.Reader], but it can be reset and recycled with [ReaderPool].
+// It never copies the data it reads from.
+//
+// The zero value is an empty reader.
+type Reader struct {
+	b []byte
+	i int64
+}
+
+// NewReader returns a new [Reader] reading from b.
+func NewReader(b []byte) *Reader {
+	return &Reader{
+		b: b,
+	}
+}
+
+// Reset resets the [Reader] to read from b.
+func (r *Reader) Reset(b []byte) {
+	r.b = b
+	r.i = 0
+}
+
+// Len returns the number of bytes of the unread portion of the data.
+func (r *Reader) Len() int {
+	if r.i >= int64(len(r.b)) {
+		return 0
+	}
+	return int(int64(len(r.b)) - r.i)
+}
+
+// Size returns the original length of the underlying data.
+//
+// The returned value is always the same and is not affected by calls to any other method.
+func (r *Reader) Size() int64 {
+	return int64(len(r.b))
+}
+
+// Read implements [io.Reader].
+func (r *Reader) Read(p []byte) (n int, err error) {
+	if r.i >= int64(len(r.b)) {
+		return 0, io.EOF
+	}
+	n = copy(p, r.b[r.i:])
+	r.i += int64(n)
+	return n, nil
+}
+
+// ReadAt implements [io.ReaderAt].
+func (r *Reader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("bytesutil.Reader.ReadAt: negative offset")
+	}
+	if off >= int64(len(r.b)) {
+		return 0, io.EOF
+	}
+	n = copy(p, r.b[off:])
+	if n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// ReadByte implements [io.ByteReader].
+func (r *Reader) ReadByte() (byte, error) {
+	if r.i >= int64(len(r.b)) {
+		return 0, io.EOF
+	}
+	c := r.b[r.i]
+	r.i++
+	return c, nil
+}
+
+// UnreadByte implements [io.ByteScanner].
+func (r *Reader) UnreadByte() error {
+	if r.i <= 0 {
+		return errors.New("bytesutil.Reader.UnreadByte: at beginning of data")
+	}
+	r.i--
+	return nil
+}
+
+// Seek implements [io.Seeker].
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.i + offset
+	case io.SeekEnd:
+		abs = int64(len(r.b)) + offset
+	default:
+		return 0, errors.New("bytesutil.Reader.Seek: invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("bytesutil.Reader.Seek: negative position")
+	}
+	r.i = abs
+	return abs, nil
+}
+
+// WriteTo implements [io.WriterTo].
+func (r *Reader) WriteTo(w io.Writer) (n int64, err error) {
+	if r.i >= int64(len(r.b)) {
+		return 0, nil
+	}
+	b := r.b[r.i:]
+	m, err := w.Write(b)
+	if m > len(b) {
+		panic("bytesutil.Reader.WriteTo: invalid Write count")
+	}
+	r.i += int64(m)
+	n = int64(m)
+	if m != len(b) && err == nil {
+		err = io.ErrShortWrite
+	}
+	return n, err //nolint:wrapcheck // Not needed.
+}
@@ -0,0 +1,30 @@
+package bytesutil
+
+import (
+	"github.com/pierrre/go-libs/syncutil"
+)
+
+// ReaderPool is a [syncutil.Pool] of [Reader].
+//
+// Readers are automatically reset.
+type ReaderPool struct {
+	pool syncutil.Pool[*Reader]
+}
+
+// Get returns a [Reader] from the pool, reading from b.
+func (p *ReaderPool) Get(b []byte) *Reader {
+	r := p.pool.Get()
+	if r == nil {
+		return NewReader(b)
+	}
+	r.Reset(b)
+	return r
+}
+
+// Put puts the [Reader] to the pool.
+//
+// It releases the reference to the underlying data.
+func (p *ReaderPool) Put(r *Reader) {
+	r.Reset(nil)
+	p.pool.Put(r)
+}
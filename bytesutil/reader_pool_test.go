@@ -0,0 +1,55 @@
+package bytesutil_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func TestReaderPool(t *testing.T) {
+	p := &ReaderPool{}
+	for range 10 {
+		r := p.Get([]byte(testWriterPoolData))
+		assert.Equal(t, r.Len(), len(testWriterPoolData))
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, string(b), testWriterPoolData)
+		p.Put(r)
+	}
+}
+
+func TestReaderPoolWriterPool(t *testing.T) {
+	wp := &WriterPool{}
+	rp := &ReaderPool{}
+	w := wp.Get()
+	w.AppendString("abc")
+	r := rp.Get(w.Bytes())
+	var dst Writer
+	n, err := r.WriteTo(&dst)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 3)
+	assert.BytesEqual(t, dst, []byte("abc"))
+	rp.Put(r)
+	wp.Put(w)
+}
+
+func TestReaderPoolAllocs(t *testing.T) {
+	p := &ReaderPool{}
+	b := []byte("abc")
+	p.Put(p.Get(b))
+	assert.AllocsPerRun(t, 100, func() {
+		r := p.Get(b)
+		p.Put(r)
+	}, 0)
+}
+
+func BenchmarkReaderPool(b *testing.B) {
+	p := &ReaderPool{}
+	data := []byte(testWriterPoolData)
+	for b.Loop() {
+		r := p.Get(data)
+		p.Put(r)
+	}
+}
@@ -0,0 +1,222 @@
+package bytesutil_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func ExampleReader() {
+	var w Writer
+	w.AppendString("abc")
+	r := w.Reader()
+	b, _ := io.ReadAll(r)
+	fmt.Println(string(b))
+	// Output: abc
+}
+
+func TestReaderRead(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	p := make([]byte, 2)
+	n, err := r.Read(p)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 2)
+	assert.BytesEqual(t, p[:n], []byte("ab"))
+	assert.Equal(t, r.Len(), 1)
+	n, err = r.Read(p)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 1)
+	assert.BytesEqual(t, p[:n], []byte("c"))
+	n, err = r.Read(p)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, n, 0)
+}
+
+func BenchmarkReaderRead(b *testing.B) {
+	r := NewReader([]byte("abc"))
+	data := []byte("abc")
+	p := make([]byte, 3)
+	for b.Loop() {
+		_, _ = r.Read(p)
+		r.Reset(data)
+	}
+}
+
+func TestReaderReadAt(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	p := make([]byte, 2)
+	n, err := r.ReadAt(p, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 2)
+	assert.BytesEqual(t, p, []byte("bc"))
+	assert.Equal(t, r.Len(), 3)
+}
+
+func TestReaderReadAtShort(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	p := make([]byte, 2)
+	n, err := r.ReadAt(p, 2)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, n, 1)
+	assert.BytesEqual(t, p[:n], []byte("c"))
+}
+
+func TestReaderReadAtEOF(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	n, err := r.ReadAt(make([]byte, 2), 3)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, n, 0)
+}
+
+func TestReaderReadAtErrorNegativeOffset(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	_, err := r.ReadAt(make([]byte, 2), -1)
+	assert.Error(t, err)
+}
+
+func TestReaderReadByte(t *testing.T) {
+	r := NewReader([]byte("ab"))
+	c, err := r.ReadByte()
+	assert.NoError(t, err)
+	assert.Equal(t, c, 'a')
+	c, err = r.ReadByte()
+	assert.NoError(t, err)
+	assert.Equal(t, c, 'b')
+	_, err = r.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReaderUnreadByte(t *testing.T) {
+	r := NewReader([]byte("ab"))
+	_, _ = r.ReadByte()
+	err := r.UnreadByte()
+	assert.NoError(t, err)
+	c, err := r.ReadByte()
+	assert.NoError(t, err)
+	assert.Equal(t, c, 'a')
+}
+
+func TestReaderUnreadByteErrorBeginning(t *testing.T) {
+	r := NewReader([]byte("ab"))
+	err := r.UnreadByte()
+	assert.Error(t, err)
+}
+
+func TestReaderSeek(t *testing.T) {
+	r := NewReader([]byte("abcdef"))
+	for _, tc := range []struct {
+		offset   int64
+		whence   int
+		expected int64
+	}{
+		{offset: 2, whence: io.SeekStart, expected: 2},
+		{offset: 1, whence: io.SeekCurrent, expected: 3},
+		{offset: -1, whence: io.SeekEnd, expected: 5},
+		{offset: 10, whence: io.SeekStart, expected: 10},
+	} {
+		pos, err := r.Seek(tc.offset, tc.whence)
+		assert.NoError(t, err)
+		assert.Equal(t, pos, tc.expected)
+	}
+	assert.Equal(t, r.Len(), 0)
+	_, err := r.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReaderSeekErrorNegative(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	_, err := r.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
+
+func TestReaderSeekErrorWhence(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	_, err := r.Seek(0, 3)
+	assert.Error(t, err)
+}
+
+func TestReaderWriteTo(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	_, _ = r.ReadByte()
+	var w Writer
+	n, err := r.WriteTo(&w)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 2)
+	assert.BytesEqual(t, w, []byte("bc"))
+	assert.Equal(t, r.Len(), 0)
+	n, err = r.WriteTo(&w)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 0)
+}
+
+func TestReaderWriteToError(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	w := writerFunc(func(p []byte) (int, error) {
+		return 1, errors.New("error")
+	})
+	n, err := r.WriteTo(w)
+	assert.Error(t, err)
+	assert.Equal(t, n, 1)
+	assert.Equal(t, r.Len(), 2)
+}
+
+func TestReaderWriteToShortWrite(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	w := writerFunc(func(p []byte) (int, error) {
+		return 1, nil
+	})
+	_, err := r.WriteTo(w)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+func TestReaderWriteToPanicInvalidCount(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	w := writerFunc(func(p []byte) (int, error) {
+		return 4, nil
+	})
+	assert.Panics(t, func() {
+		_, _ = r.WriteTo(w)
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func BenchmarkReaderWriteTo(b *testing.B) {
+	data := []byte("abc")
+	r := NewReader(data)
+	var w Writer
+	for b.Loop() {
+		_, _ = r.WriteTo(&w)
+		r.Reset(data)
+		w.Reset()
+	}
+}
+
+func TestReaderSize(t *testing.T) {
+	r := NewReader([]byte("abc"))
+	_, _ = r.ReadByte()
+	assert.Equal(t, r.Size(), 3)
+}
+
+func TestReaderZero(t *testing.T) {
+	var r Reader
+	assert.Equal(t, r.Len(), 0)
+	_, err := r.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWriterReader(t *testing.T) {
+	w := Writer("abc")
+	r := w.Reader()
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.BytesEqual(t, b, []byte("abc"))
+}
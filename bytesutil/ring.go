@@ -0,0 +1,203 @@
+package bytesutil
+
+import (
+	"bytes"
+	"io"
+	"slices"
+)
+
+// Ring is a fixed-capacity buffer that keeps the last written bytes.
+//
+// When it is full, writing overwrites the oldest bytes.
+// It is useful to capture the tail of an output (e.g. a subprocess or a log stream).
+//
+// Writing never allocates.
+// It must be created with [NewRing].
+type Ring struct {
+	buf     []byte
+	start   int  // Index of the oldest byte.
+	len     int  // Number of stored bytes.
+	dropped bool // Some bytes were overwritten.
+	prev    byte // Last overwritten byte, if dropped is true.
+}
+
+// NewRing returns a new [Ring] with the given capacity.
+func NewRing(size int) *Ring {
+	if size < 0 {
+		panic("bytesutil.NewRing: negative size")
+	}
+	return &Ring{
+		buf: make([]byte, size),
+	}
+}
+
+// Write appends p to the ring, overwriting the oldest bytes if necessary.
+//
+// Write always returns len(p), nil.
+func (r *Ring) Write(p []byte) (n int, err error) {
+	ringWrite(r, p)
+	return len(p), nil
+}
+
+// WriteString appends s to the ring, overwriting the oldest bytes if necessary.
+//
+// WriteString always returns len(s), nil.
+func (r *Ring) WriteString(s string) (n int, err error) {
+	ringWrite(r, s)
+	return len(s), nil
+}
+
+// WriteByte appends c to the ring, overwriting the oldest byte if necessary.
+//
+// WriteByte always returns nil.
+func (r *Ring) WriteByte(c byte) error {
+	size := len(r.buf)
+	if size == 0 {
+		return nil
+	}
+	i := r.start + r.len
+	if r.len == size {
+		r.prev, r.dropped = r.buf[r.start], true
+		r.start = (r.start + 1) % size
+	} else {
+		r.len++
+	}
+	r.buf[i%size] = c
+	return nil
+}
+
+func ringWrite[S string | []byte](r *Ring, p S) {
+	size := len(r.buf)
+	if size == 0 || len(p) == 0 {
+		return
+	}
+	if len(p) >= size {
+		switch {
+		case len(p) > size:
+			r.prev, r.dropped = p[len(p)-size-1], true
+		case r.len > 0:
+			r.prev, r.dropped = r.buf[(r.start+r.len-1)%size], true
+		}
+		copy(r.buf, p[len(p)-size:])
+		r.start = 0
+		r.len = size
+		return
+	}
+	overwritten := r.len + len(p) - size
+	if overwritten > 0 {
+		r.prev, r.dropped = r.buf[(r.start+overwritten-1)%size], true
+	}
+	i := (r.start + r.len) % size
+	m := copy(r.buf[i:], p)
+	copy(r.buf, p[m:])
+	if overwritten > 0 {
+		r.start = (r.start + overwritten) % size
+		r.len = size
+	} else {
+		r.len += len(p)
+	}
+}
+
+// Len returns the number of bytes currently stored in the ring.
+func (r *Ring) Len() int {
+	return r.len
+}
+
+// Cap returns the capacity of the ring.
+func (r *Ring) Cap() int {
+	return len(r.buf)
+}
+
+// Bytes returns the contents of the ring, from the oldest byte to the newest.
+//
+// The underlying storage is rotated in place if necessary, so it doesn't allocate.
+// The returned slice is only valid until the next modification of the ring.
+func (r *Ring) Bytes() []byte {
+	if r.start+r.len > len(r.buf) {
+		slices.Reverse(r.buf[:r.start])
+		slices.Reverse(r.buf[r.start:])
+		slices.Reverse(r.buf)
+		r.start = 0
+	}
+	return r.buf[r.start : r.start+r.len]
+}
+
+// AppendTo appends the contents of the ring to dst.
+func (r *Ring) AppendTo(dst []byte) []byte {
+	b1, b2 := r.segments()
+	dst = append(dst, b1...)
+	dst = append(dst, b2...)
+	return dst
+}
+
+// String returns the contents of the ring as a string.
+func (r *Ring) String() string {
+	b1, b2 := r.segments()
+	return string(b1) + string(b2)
+}
+
+// WriteTo writes the contents of the ring to w.
+//
+// The contents of the ring are not modified.
+func (r *Ring) WriteTo(w io.Writer) (n int64, err error) {
+	b1, b2 := r.segments()
+	for _, b := range [2][]byte{b1, b2} {
+		if len(b) == 0 {
+			continue
+		}
+		m, err := w.Write(b)
+		n += int64(m)
+		if err != nil {
+			return n, err //nolint:wrapcheck // Not needed.
+		}
+		if m != len(b) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+func (r *Ring) segments() (b1, b2 []byte) {
+	end := r.start + r.len
+	if end <= len(r.buf) {
+		return r.buf[r.start:end], nil
+	}
+	return r.buf[r.start:], r.buf[:end-len(r.buf)]
+}
+
+// Lines returns the last n complete lines of the ring, including their trailing '\n'.
+//
+// A line is complete if it is terminated by '\n' and its beginning was not overwritten.
+// It may return less than n lines.
+// Like [Ring.Bytes], it doesn't allocate and the returned slice is only valid until the next modification of the ring.
+func (r *Ring) Lines(n int) []byte {
+	b := r.Bytes()
+	end := bytes.LastIndexByte(b, '\n') + 1
+	if end == 0 || n <= 0 {
+		return nil
+	}
+	start := end - 1
+	for ; n > 0; n-- {
+		start = bytes.LastIndexByte(b[:start], '\n')
+		if start < 0 {
+			break
+		}
+	}
+	if start >= 0 {
+		return b[start+1 : end]
+	}
+	if r.dropped && r.prev != '\n' {
+		// The first line is truncated.
+		start = bytes.IndexByte(b, '\n')
+		return b[start+1 : end]
+	}
+	return b[:end]
+}
+
+// Reset resets the ring to be empty.
+func (r *Ring) Reset() {
+	r.start = 0
+	r.len = 0
+	r.dropped = false
+	r.prev = 0
+}
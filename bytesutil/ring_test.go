@@ -0,0 +1,192 @@
+package bytesutil_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func ExampleRing() {
+	r := NewRing(10)
+	_, _ = r.WriteString("line 1\nline 2\nline 3\n")
+	fmt.Printf("%q\n", r.Bytes())
+	fmt.Printf("%q\n", r.Lines(2))
+	// Output:
+	// " 2\nline 3\n"
+	// "line 3\n"
+}
+
+func TestRingWrite(t *testing.T) {
+	r := NewRing(5)
+	for _, tc := range []struct {
+		write    string
+		expected string
+	}{
+		{write: "ab", expected: "ab"},
+		{write: "cd", expected: "abcd"},
+		{write: "efg", expected: "cdefg"},
+		{write: "", expected: "cdefg"},
+		{write: "h", expected: "defgh"},
+		{write: "ijklmnop", expected: "lmnop"},
+		{write: "qrstu", expected: "qrstu"},
+	} {
+		n, err := r.Write([]byte(tc.write))
+		assert.NoError(t, err)
+		assert.Equal(t, n, len(tc.write))
+		assert.Equal(t, r.String(), tc.expected)
+		assert.Equal(t, string(r.AppendTo(nil)), tc.expected)
+		assert.Equal(t, r.Len(), len(tc.expected))
+	}
+}
+
+func BenchmarkRingWrite(b *testing.B) {
+	r := NewRing(100)
+	p := []byte("abcdefghijklmnopqrstuvwxyz\n")
+	for b.Loop() {
+		_, _ = r.Write(p)
+	}
+}
+
+func TestRingWriteString(t *testing.T) {
+	r := NewRing(5)
+	n, err := r.WriteString("abcdefg")
+	assert.NoError(t, err)
+	assert.Equal(t, n, 7)
+	assert.Equal(t, r.String(), "cdefg")
+}
+
+func TestRingWriteByte(t *testing.T) {
+	r := NewRing(3)
+	for _, c := range []byte("abcde") {
+		err := r.WriteByte(c)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, r.String(), "cde")
+}
+
+func TestRingZeroCapacity(t *testing.T) {
+	r := NewRing(0)
+	_, _ = r.WriteString("abc")
+	_ = r.WriteByte('a')
+	assert.Equal(t, r.Len(), 0)
+	assert.Equal(t, r.Cap(), 0)
+	assert.SliceEmpty(t, r.Bytes())
+}
+
+func TestRingPanicNegative(t *testing.T) {
+	assert.Panics(t, func() {
+		NewRing(-1)
+	})
+}
+
+func TestRingWriteAllocs(t *testing.T) {
+	r := NewRing(10)
+	assert.AllocsPerRun(t, 100, func() {
+		_, _ = r.WriteString("abcdefg")
+		_ = r.WriteByte('\n')
+		_ = r.Bytes()
+		_ = r.Lines(1)
+	}, 0)
+}
+
+func TestRingBytes(t *testing.T) {
+	r := NewRing(5)
+	_, _ = r.WriteString("abcdefg")
+	b := r.Bytes()
+	assert.BytesEqual(t, b, []byte("cdefg"))
+	_, _ = r.WriteString("hi")
+	assert.Equal(t, r.String(), "efghi")
+}
+
+func TestRingWriteTo(t *testing.T) {
+	r := NewRing(5)
+	_, _ = r.WriteString("abcdefg")
+	var w Writer
+	n, err := r.WriteTo(&w)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 5)
+	assert.Equal(t, w.String(), "cdefg")
+	assert.Equal(t, r.String(), "cdefg")
+}
+
+func TestRingWriteToError(t *testing.T) {
+	r := NewRing(5)
+	_, _ = r.WriteString("abcdefg")
+	_, err := r.WriteTo(writerFunc(func(p []byte) (int, error) {
+		return 0, errors.New("error")
+	}))
+	assert.Error(t, err)
+}
+
+func TestRingWriteToShortWrite(t *testing.T) {
+	r := NewRing(5)
+	_, _ = r.WriteString("abc")
+	_, err := r.WriteTo(writerFunc(func(p []byte) (int, error) {
+		return 1, nil
+	}))
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+func TestRingLines(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		size     int
+		write    string
+		n        int
+		expected string
+	}{
+		{name: "Empty", size: 10, write: "", n: 1, expected: ""},
+		{name: "NoLine", size: 10, write: "abc", n: 1, expected: ""},
+		{name: "Zero", size: 10, write: "a\nb\n", n: 0, expected: ""},
+		{name: "One", size: 10, write: "a\nb\n", n: 1, expected: "b\n"},
+		{name: "Two", size: 10, write: "a\nb\n", n: 2, expected: "a\nb\n"},
+		{name: "More", size: 10, write: "a\nb\n", n: 3, expected: "a\nb\n"},
+		{name: "Partial", size: 10, write: "a\nb\nc", n: 1, expected: "b\n"},
+		{name: "Truncated", size: 10, write: "aaaaa\nbbbbb\n", n: 3, expected: "bbbbb\n"},
+		{name: "DroppedLine", size: 6, write: "aaaaa\nbbbbb\n", n: 3, expected: "bbbbb\n"},
+		{name: "TruncatedNewline", size: 7, write: "aaaaa\nbbbbb\n", n: 3, expected: "bbbbb\n"},
+		{name: "TruncatedEmpty", size: 12, write: "x\naaaaa\nbbbb\n", n: 3, expected: "aaaaa\nbbbb\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRing(tc.size)
+			_, _ = r.WriteString(tc.write)
+			assert.Equal(t, string(r.Lines(tc.n)), tc.expected)
+		})
+	}
+}
+
+func TestRingLinesByte(t *testing.T) {
+	r := NewRing(6)
+	for _, c := range []byte("aaaa\nbbbb\n") {
+		_ = r.WriteByte(c)
+	}
+	assert.Equal(t, string(r.Lines(2)), "bbbb\n")
+	r = NewRing(5)
+	for _, c := range []byte("aaaa\nbbbb\n") {
+		_ = r.WriteByte(c)
+	}
+	assert.Equal(t, string(r.Lines(2)), "bbbb\n")
+}
+
+func BenchmarkRingLines(b *testing.B) {
+	r := NewRing(100)
+	for range 10 {
+		_, _ = r.WriteString("abcdefghijklmnopqrstuvwxyz\n")
+	}
+	for b.Loop() {
+		_ = r.Lines(2)
+	}
+}
+
+func TestRingReset(t *testing.T) {
+	r := NewRing(5)
+	_, _ = r.WriteString("abcdefg\n")
+	r.Reset()
+	assert.Equal(t, r.Len(), 0)
+	_, _ = r.WriteString("a\n")
+	assert.Equal(t, string(r.Lines(1)), "a\n")
+}
@@ -1,7 +1,9 @@
 package bytesutil
 
 import (
+	"errors"
 	"io"
+	"os"
 	"unicode/utf8"
 )
 
@@ -69,15 +71,64 @@ func (w *Writer) WriteRune(r rune) (n int, err error) {
 
 // ReadFrom reads data from r until EOF and appends it to the writer.
 // It returns the number of bytes read and any error encountered.
+//
+// If r reports its size (see [Writer.ReadFromLimit]), the writer is grown accordingly before reading.
 func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
+	return w.readFrom(r, -1)
+}
+
+// ErrTooLarge is returned by [Writer.ReadFromLimit] if the reader contains more data than the limit.
+var ErrTooLarge = errors.New("bytesutil.Writer: too large")
+
+// ReadFromLimit is like [Writer.ReadFrom], but it reads at most limit bytes.
+// If r contains more data, it returns [ErrTooLarge], and the writer contains the first limit bytes.
+//
+// If r reports its size, the writer is grown accordingly before reading.
+// The size is reported by a Len() int method (e.g. [bytes.Reader]) or the Stat() method of [os.File].
+// The N field of [io.LimitedReader] is used as an upper bound: it caps the growth, but it is not preallocated.
+//
+// It panics if limit is negative.
+func (w *Writer) ReadFromLimit(r io.Reader, limit int64) (n int64, err error) {
+	if limit < 0 {
+		panic("bytesutil.Writer.ReadFromLimit: negative limit")
+	}
+	return w.readFrom(r, limit)
+}
+
+const readFromMinRead = 4096
+
+// readFrom reads from r.
+// A negative limit means no limit.
+func (w *Writer) readFrom(r io.Reader, limit int64) (n int64, err error) {
+	size, exact, ok := readerSize(r)
+	if ok && limit >= 0 {
+		size = min(size, limit)
+	}
+	if ok && exact {
+		w.grow(int(size) + 1) // +1 allows to detect EOF without growing.
+	}
 	for {
-		w.grow(4096)
-		m, e := r.Read((*w)[len(*w):cap(*w)])
+		if w.Available() == 0 {
+			grow := int64(readFromMinRead)
+			if ok && !exact {
+				grow = min(grow, max(size-n, 0)+1) // The upper bound only caps the growth, it is not preallocated.
+			}
+			w.grow(int(grow))
+		}
+		buf := (*w)[len(*w):cap(*w)]
+		if limit >= 0 {
+			buf = buf[:min(int64(len(buf)), limit-n+1)] // +1 allows to detect if the limit is exceeded.
+		}
+		m, e := r.Read(buf)
 		if m < 0 {
 			panic("bytesutil.Writer.ReadFrom: reader returned negative count from Read")
 		}
 		*w = (*w)[:len(*w)+m]
 		n += int64(m)
+		if limit >= 0 && n > limit {
+			*w = (*w)[:int64(len(*w))-(n-limit)]
+			return limit, ErrTooLarge
+		}
 		if e != nil {
 			if e == io.EOF {
 				return n, nil
@@ -87,6 +138,33 @@ func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 }
 
+// readerSize returns the size reported by the reader.
+// If exact is false, the size is an upper bound.
+func readerSize(r io.Reader) (size int64, exact, ok bool) {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len()), true, true
+	case *os.File:
+		fi, err := r.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0, false, false
+		}
+		pos, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fi.Size(), false, true
+		}
+		return max(fi.Size()-pos, 0), true, true
+	case *io.LimitedReader:
+		n := max(r.N, 0)
+		size, exact, ok = readerSize(r.R)
+		if ok && size <= n {
+			return size, exact, true
+		}
+		return n, false, true
+	}
+	return 0, false, false
+}
+
 // Reset resets the writer to be empty, while keeping the underlying storage.
 func (w *Writer) Reset() {
 	*w = (*w)[:0]
@@ -142,6 +220,13 @@ func (w Writer) Bytes() []byte {
 	return w
 }
 
+// Reader returns a [Reader] reading from the contents of the writer.
+//
+// The data is not copied: the writer must not be modified while the reader is used.
+func (w Writer) Reader() *Reader {
+	return NewReader(w)
+}
+
 // Clone returns a copy of the writer's contents.
 func (w Writer) Clone() Writer {
 	if w == nil {
@@ -2,17 +2,30 @@ package bytesutil
 
 import (
 	"cmp"
+	"math/bits"
+	"sync/atomic"
 
 	"github.com/pierrre/go-libs/syncutil"
 )
 
-const writerPoolMaxCapDefault = 1 << 16 // 64 KiB
+const (
+	writerPoolMaxCapDefault = 1 << 16 // 64 KiB
+	writerPoolMinClass      = 6       // 64 B
+	writerPoolClasses       = bits.UintSize - 1 - writerPoolMinClass
+)
 
 // WriterPool is a [syncutil.Pool] of [Writer].
 //
+// Writers are stored in power-of-two size classes, according to their capacity.
+// It prevents small requests from getting large writers, and allows to recycle large writers.
+//
 // Writers are automatically reset.
 type WriterPool struct {
-	pool syncutil.Pool[*Writer]
+	pools    [writerPoolClasses]syncutil.Pool[*Writer]
+	filled   atomic.Uint64 // Bitmap of the classes that may contain writers.
+	last     atomic.Int32  // Class of the last writer that was put.
+	stats    writerPoolStats
+	adaptive writerPoolAdaptive
 
 	// MaxCap defines the maximum capacity accepted for recycled writers.
 	// If Put() is called with a writer larger than this value, it's discarded.
@@ -24,27 +37,150 @@ type WriterPool struct {
 	// Clear indicates whether to clear the writer before putting it back to the pool.
 	// It prevents leaking sensitive data, but has a small performance cost.
 	Clear bool
+
+	// CollectStats indicates whether to collect statistics, see [WriterPool.Stats].
+	// It has a small performance cost.
+	CollectStats bool
+
+	// Adaptive indicates whether to adapt the capacity of recycled writers to the recently used lengths.
+	// The pool tracks the 95th percentile of the lengths of the writers passed to Put().
+	// If a writer's capacity is more than 4 times this value, Put() replaces it with a right-sized writer.
+	// It keeps the memory usage low after occasional large writers.
+	Adaptive bool
 }
 
 // Get returns a [Writer] from the pool.
+//
+// It first looks for a writer in the size class of the last writer that was put, then in the other non-empty size classes from the smallest one.
+// If the expected size is known, prefer [WriterPool.GetSize].
 func (p *WriterPool) Get() *Writer {
-	w := p.pool.Get()
+	w := p.getRecycled()
 	if w == nil {
-		return new(Writer)
+		w = new(Writer)
 	}
 	return w
 }
 
+func (p *WriterPool) getRecycled() *Writer {
+	filled := p.filled.Load()
+	if filled == 0 {
+		return p.miss()
+	}
+	last := int(p.last.Load())
+	if filled&(1<<last) != 0 {
+		w := p.getClass(last)
+		if w != nil {
+			return p.hit(w)
+		}
+	}
+	for filled &^= 1 << last; filled != 0; filled &= filled - 1 {
+		w := p.getClass(bits.TrailingZeros64(filled))
+		if w != nil {
+			return p.hit(w)
+		}
+	}
+	return p.miss()
+}
+
+// getClass returns a writer from a size class.
+//
+// If the class is empty, it is removed from the filled bitmap, so the following calls don't look for it.
+// (Each miss of [sync.Pool] scans all Ps.)
+func (p *WriterPool) getClass(c int) *Writer {
+	w := p.pools[c].Get()
+	if w == nil {
+		p.filled.And(^(uint64(1) << c))
+	}
+	return w
+}
+
+// GetSize returns a [Writer] from the pool, with a capacity of at least hint.
+//
+// It looks for a writer in the size class of hint, then in the next one.
+// If none is available, it allocates a new writer with a capacity rounded up to the size class.
+func (p *WriterPool) GetSize(hint int) *Writer {
+	if hint <= 0 {
+		return p.Get()
+	}
+	c := writerPoolClassGet(hint)
+	filled := p.filled.Load()
+	for i := c; i < min(c+2, writerPoolClasses); i++ {
+		if filled&(1<<i) == 0 {
+			continue
+		}
+		w := p.getClass(i)
+		if w != nil {
+			w.Grow(hint) // The smallest class also contains writers smaller than 64 B.
+			return p.hit(w)
+		}
+	}
+	p.miss()
+	size := hint
+	if c < writerPoolClasses {
+		size = 1 << (c + writerPoolMinClass)
+	}
+	return new(make(Writer, 0, size))
+}
+
 // Put puts the [Writer] to the Pool.
+//
+// The writer is stored in the size class matching its capacity.
+// Writers smaller than 64 B are stored in the smallest size class.
 // WARNING: the caller MUST NOT reuse the writer's content after this call.
 func (p *WriterPool) Put(w *Writer) {
+	if p.CollectStats {
+		p.stats.put(w.Cap())
+	}
+	if p.Adaptive && p.adaptive.shrink(w) && p.CollectStats {
+		p.stats.shrinks.Add(1)
+	}
 	maxCap := cmp.Or(p.MaxCap, writerPoolMaxCapDefault)
-	if maxCap < 0 || w.Cap() <= maxCap {
-		if p.Clear {
-			w.Clear()
-		} else {
-			w.Reset()
+	if maxCap >= 0 && w.Cap() > maxCap {
+		if p.CollectStats {
+			p.stats.discards.Add(1)
 		}
-		p.pool.Put(w)
+		return
+	}
+	c := writerPoolClassPut(w.Cap())
+	if p.Clear {
+		w.Clear()
+	} else {
+		w.Reset()
+	}
+	p.pools[c].Put(w)
+	if int(p.last.Load()) != c {
+		p.last.Store(int32(c))
+	}
+	bit := uint64(1) << c
+	if p.filled.Load()&bit == 0 {
+		p.filled.Or(bit)
+	}
+}
+
+func (p *WriterPool) hit(w *Writer) *Writer {
+	if p.CollectStats {
+		p.stats.hits.Add(1)
+	}
+	return w
+}
+
+func (p *WriterPool) miss() *Writer {
+	if p.CollectStats {
+		p.stats.misses.Add(1)
 	}
+	return nil
+}
+
+// writerPoolClassGet returns the class where all writers have a capacity of at least size.
+//
+// The returned value may be out of range if size is too large.
+func writerPoolClassGet(size int) int {
+	return max(bits.Len(uint(size-1))-writerPoolMinClass, 0)
+}
+
+// writerPoolClassPut returns the class that contains a writer with the capacity.
+//
+// It returns the smallest class if the capacity is too small.
+func writerPoolClassPut(capacity int) int {
+	return max(bits.Len(uint(capacity))-1-writerPoolMinClass, 0)
 }
@@ -0,0 +1,61 @@
+package bytesutil
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+const (
+	writerPoolAdaptiveCalibrateCalls = 1 << 10
+	writerPoolAdaptivePercentile     = 95
+	writerPoolAdaptiveFactor         = 4
+)
+
+// writerPoolAdaptive tracks the lengths of the writers in order to shrink oversized writers.
+//
+// The lengths are stored in a histogram of power-of-two ranges.
+// The histogram is decayed at each calibration, so the percentile reflects the recent lengths.
+type writerPoolAdaptive struct {
+	lens    [bits.UintSize]atomic.Uint64
+	calls   atomic.Uint64
+	typical atomic.Int64 // 0 means not calibrated yet.
+}
+
+// shrink records the length of the writer and replaces it with a right-sized writer if it's oversized.
+//
+// It returns true if the writer was replaced.
+func (a *writerPoolAdaptive) shrink(w *Writer) bool {
+	a.lens[bits.Len(uint(w.Len()))].Add(1)
+	if a.calls.Add(1)%writerPoolAdaptiveCalibrateCalls == 0 {
+		a.calibrate()
+	}
+	typical := int(a.typical.Load())
+	if typical == 0 || w.Cap()/writerPoolAdaptiveFactor <= typical {
+		return false
+	}
+	*w = make(Writer, 0, typical)
+	return true
+}
+
+func (a *writerPoolAdaptive) calibrate() {
+	var counts [bits.UintSize]uint64
+	var total uint64
+	for i := range a.lens {
+		n := a.lens[i].Load()
+		counts[i] = n
+		total += n
+		a.lens[i].Add(-(n / 2)) // Decay.
+	}
+	if total == 0 {
+		return
+	}
+	threshold := total * writerPoolAdaptivePercentile / 100
+	var sum uint64
+	for i, n := range counts {
+		sum += n
+		if sum >= threshold {
+			a.typical.Store(int64(1) << min(max(i, writerPoolMinClass), bits.UintSize-2))
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package bytesutil_test
+
+import (
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func TestWriterPoolAdaptive(t *testing.T) {
+	p := &WriterPool{
+		Adaptive:     true,
+		CollectStats: true,
+	}
+	large := new(make(Writer, 0, 1<<15))
+	p.Put(large)
+	assert.Equal(t, large.Cap(), 1<<15)
+	for range 1 << 10 {
+		w := p.GetSize(100)
+		w.AppendString(testWriterPoolData[:100])
+		p.Put(w)
+	}
+	large = new(make(Writer, 0, 1<<15))
+	large.AppendString(testWriterPoolData)
+	p.Put(large)
+	assert.Equal(t, large.Cap(), 128)
+	st := p.Stats()
+	assert.Equal(t, st.Shrinks, 1)
+}
+
+func TestWriterPoolAdaptiveNotOversized(t *testing.T) {
+	p := &WriterPool{
+		Adaptive: true,
+	}
+	for range 1 << 10 {
+		w := p.GetSize(100)
+		w.AppendString(testWriterPoolData[:100])
+		p.Put(w)
+	}
+	w := new(make(Writer, 0, 512))
+	p.Put(w)
+	assert.Equal(t, w.Cap(), 512)
+}
+
+func TestWriterPoolAdaptiveLargerThanMaxCap(t *testing.T) {
+	p := &WriterPool{
+		Adaptive: true,
+		MaxCap:   1 << 10,
+	}
+	for range 1 << 10 {
+		w := p.GetSize(100)
+		w.AppendString(testWriterPoolData[:100])
+		p.Put(w)
+	}
+	p.Put(new(make(Writer, 0, 1<<15)))
+	w := p.GetSize(100)
+	assert.LessOrEqual(t, w.Cap(), 256)
+}
+
+func BenchmarkWriterPoolAdaptive(b *testing.B) {
+	p := &WriterPool{
+		Adaptive: true,
+	}
+	for b.Loop() {
+		w := p.Get()
+		w.AppendString(testWriterPoolData)
+		p.Put(w)
+	}
+}
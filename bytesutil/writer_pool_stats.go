@@ -0,0 +1,76 @@
+package bytesutil
+
+import (
+	"encoding/json"
+	"math/bits"
+	"sync/atomic"
+)
+
+// WriterPoolStats is a snapshot of the statistics of a [WriterPool].
+type WriterPoolStats struct {
+	// Hits is the number of recycled writers returned by Get().
+	Hits uint64
+	// Misses is the number of new writers allocated by Get().
+	Misses uint64
+	// Puts is the number of writers passed to Put().
+	Puts uint64
+	// Discards is the number of writers discarded by Put() because their capacity was larger than MaxCap.
+	Discards uint64
+	// Shrinks is the number of writers replaced by a right-sized writer in Put(), see Adaptive.
+	Shrinks uint64
+	// Caps is the distribution of the capacity of the writers passed to Put().
+	// The key is the lower bound of a power-of-two range (0 for empty writers), and the value is the number of writers in this range.
+	Caps map[int]uint64
+}
+
+type writerPoolStats struct {
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	discards atomic.Uint64
+	shrinks  atomic.Uint64
+	caps     [bits.UintSize]atomic.Uint64
+}
+
+func (s *writerPoolStats) put(capacity int) {
+	s.caps[bits.Len(uint(capacity))].Add(1)
+}
+
+// Stats returns a snapshot of the statistics.
+//
+// Statistics are only collected if CollectStats is true.
+func (p *WriterPool) Stats() WriterPoolStats {
+	st := WriterPoolStats{
+		Hits:     p.stats.hits.Load(),
+		Misses:   p.stats.misses.Load(),
+		Discards: p.stats.discards.Load(),
+		Shrinks:  p.stats.shrinks.Load(),
+		Caps:     make(map[int]uint64),
+	}
+	for i := range p.stats.caps {
+		n := p.stats.caps[i].Load()
+		if n == 0 {
+			continue
+		}
+		st.Puts += n
+		c := 0
+		if i > 0 {
+			c = 1 << (i - 1)
+		}
+		st.Caps[c] = n
+	}
+	return st
+}
+
+// WriterPoolStatsVar is an [expvar.Var] that reports the statistics of a [WriterPool].
+//
+// It can be published with [expvar.Publish].
+// The CollectStats field of the pool must be enabled.
+type WriterPoolStatsVar struct {
+	Pool *WriterPool
+}
+
+// String returns the statistics encoded as JSON.
+func (v WriterPoolStatsVar) String() string {
+	b, _ := json.Marshal(v.Pool.Stats())
+	return string(b)
+}
@@ -0,0 +1,77 @@
+package bytesutil_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/pierrre/assert"
+	. "github.com/pierrre/go-libs/bytesutil"
+)
+
+func TestWriterPoolStats(t *testing.T) {
+	p := &WriterPool{
+		CollectStats: true,
+		MaxCap:       1 << 10,
+	}
+	w := p.Get()
+	w.AppendString(testWriterPoolData)
+	p.Put(w)
+	p.Put(new(make(Writer, 0, 1<<11)))
+	st := p.Stats()
+	assert.GreaterOrEqual(t, st.Misses, 1)
+	assert.Equal(t, st.Hits+st.Misses, 1)
+	assert.Equal(t, st.Puts, 2)
+	assert.Equal(t, st.Discards, 1)
+	assert.Equal(t, st.Caps[256], 1)
+	assert.Equal(t, st.Caps[2048], 1)
+}
+
+func TestWriterPoolStatsHit(t *testing.T) {
+	p := &WriterPool{
+		CollectStats: true,
+	}
+	for range 10 {
+		w := p.GetSize(100)
+		p.Put(w)
+	}
+	st := p.Stats()
+	assert.Equal(t, st.Hits+st.Misses, 10)
+	assert.Equal(t, st.Puts, 10)
+	assert.Equal(t, st.Caps[128], 10)
+}
+
+func TestWriterPoolStatsDisabled(t *testing.T) {
+	p := &WriterPool{}
+	w := p.Get()
+	p.Put(w)
+	st := p.Stats()
+	assert.Zero(t, st.Hits)
+	assert.Zero(t, st.Misses)
+	assert.Zero(t, st.Puts)
+	assert.MapEmpty(t, st.Caps)
+}
+
+func BenchmarkWriterPoolStats(b *testing.B) {
+	p := &WriterPool{
+		CollectStats: true,
+	}
+	for b.Loop() {
+		w := p.Get()
+		w.AppendString(testWriterPoolData)
+		p.Put(w)
+	}
+}
+
+func TestWriterPoolStatsVar(t *testing.T) {
+	p := &WriterPool{
+		CollectStats: true,
+	}
+	p.Put(p.GetSize(100))
+	var v expvar.Var = WriterPoolStatsVar{Pool: p}
+	var st WriterPoolStats
+	err := json.Unmarshal([]byte(v.String()), &st)
+	assert.NoError(t, err)
+	assert.Equal(t, st.Puts, 1)
+	assert.Equal(t, st.Caps[128], 1)
+}
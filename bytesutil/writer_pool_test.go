@@ -5,6 +5,7 @@ import (
 
 	"github.com/pierrre/assert"
 	. "github.com/pierrre/go-libs/bytesutil"
+	"github.com/pierrre/go-libs/raceutil"
 )
 
 const testWriterPoolData = "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat. Duis aute irure dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla pariatur. Excepteur sint occaecat cupidatat non proident, sunt in culpa qui officia deserunt mollit anim id est laborum." //nolint:lll // This is a long text for benchmark.
@@ -54,3 +55,117 @@ func BenchmarkWriterPoolClear(b *testing.B) {
 		p.Put(w)
 	}
 }
+
+func TestWriterPoolGetSize(t *testing.T) {
+	p := &WriterPool{}
+	for _, hint := range []int{0, 1, 64, 65, 1000, 1 << 16, 1<<16 + 1} {
+		for range 10 {
+			w := p.GetSize(hint)
+			assert.Equal(t, w.Len(), 0)
+			assert.GreaterOrEqual(t, w.Cap(), hint)
+			w.AppendString(testWriterPoolData)
+			p.Put(w)
+		}
+	}
+}
+
+func BenchmarkWriterPoolGetSize(b *testing.B) {
+	p := &WriterPool{}
+	for b.Loop() {
+		w := p.GetSize(len(testWriterPoolData))
+		w.AppendString(testWriterPoolData)
+		p.Put(w)
+	}
+}
+
+func TestWriterPoolGetSizeClass(t *testing.T) {
+	p := &WriterPool{}
+	w := p.GetSize(100)
+	assert.Equal(t, w.Cap(), 128)
+	w = p.GetSize(128)
+	assert.Equal(t, w.Cap(), 128)
+}
+
+func TestWriterPoolGetSizeNotLarger(t *testing.T) {
+	p := &WriterPool{}
+	p.Put(new(make(Writer, 0, 1<<15)))
+	w := p.GetSize(100)
+	assert.Equal(t, w.Cap(), 128)
+}
+
+func TestWriterPoolGetSizeNotSmaller(t *testing.T) {
+	p := &WriterPool{}
+	p.Put(new(make(Writer, 0, 100)))
+	w := p.GetSize(100)
+	assert.GreaterOrEqual(t, w.Cap(), 100)
+}
+
+func TestWriterPoolPutSmall(t *testing.T) {
+	if raceutil.Enabled {
+		t.Skip("sync.Pool randomly drops items with the race detector")
+	}
+	p := &WriterPool{}
+	p.Put(new(make(Writer, 0, 10)))
+	w := p.Get()
+	assert.Equal(t, w.Cap(), 10)
+}
+
+func TestWriterPoolPutSmallGetSize(t *testing.T) {
+	p := &WriterPool{}
+	p.Put(new(make(Writer, 0, 10)))
+	w := p.GetSize(50)
+	assert.GreaterOrEqual(t, w.Cap(), 50)
+}
+
+func TestWriterPoolGetMixedSizes(t *testing.T) {
+	if raceutil.Enabled {
+		t.Skip("sync.Pool randomly drops items with the race detector")
+	}
+	p := &WriterPool{
+		CollectStats: true,
+	}
+	for _, c := range []int{512, 1024, 2048, 4096} {
+		p.Put(new(make(Writer, 0, c)))
+	}
+	var caps []int
+	for range 4 {
+		caps = append(caps, p.Get().Cap())
+	}
+	assert.SliceEqual(t, caps, []int{4096, 512, 1024, 2048})
+	st := p.Stats()
+	assert.Equal(t, st.Hits, 4)
+	assert.Equal(t, st.Misses, 0)
+}
+
+func TestWriterPoolAllocs(t *testing.T) {
+	if raceutil.Enabled {
+		t.Skip("sync.Pool randomly drops items with the race detector")
+	}
+	p := &WriterPool{}
+	allocs := testing.AllocsPerRun(100, func() {
+		w := p.Get()
+		w.AppendString("hello world")
+		p.Put(w)
+	})
+	assert.Equal(t, allocs, 0)
+}
+
+func TestWriterPoolPutDiscardMaxCap(t *testing.T) {
+	p := &WriterPool{
+		MaxCap: 1 << 10,
+	}
+	p.Put(new(make(Writer, 0, 1<<11)))
+	w := p.Get()
+	assert.Equal(t, w.Cap(), 0)
+}
+
+func TestWriterPoolMaxCapNoLimit(t *testing.T) {
+	p := &WriterPool{
+		MaxCap: -1,
+	}
+	for range 10 {
+		w := p.GetSize(1 << 20)
+		assert.GreaterOrEqual(t, w.Cap(), 1<<20)
+		p.Put(w)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pierrre/assert"
@@ -216,6 +218,146 @@ func BenchmarkWriterReadFrom(b *testing.B) {
 	}
 }
 
+func TestWriterReadFromPrealloc(t *testing.T) {
+	var w Writer
+	data := bytes.Repeat([]byte("a"), 10000)
+	n, err := w.ReadFrom(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10000)
+	assert.BytesEqual(t, w, data)
+	r := bytes.NewReader(data)
+	assert.AllocsPerRun(t, 10, func() {
+		w = nil
+		r.Reset(data)
+		_, _ = w.ReadFrom(r)
+	}, 1)
+}
+
+func TestWriterReadFromLimit(t *testing.T) {
+	var w Writer
+	n, err := w.ReadFromLimit(bytes.NewReader([]byte("abc")), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 3)
+	assert.BytesEqual(t, w, []byte("abc"))
+}
+
+func TestWriterReadFromLimitErrorTooLarge(t *testing.T) {
+	var w Writer
+	n, err := w.ReadFromLimit(bytes.NewReader([]byte("abcd")), 3)
+	assert.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, n, 3)
+	assert.BytesEqual(t, w, []byte("abc"))
+}
+
+func TestWriterReadFromLimitErrorTooLargeNoSize(t *testing.T) {
+	w := Writer("0")
+	data := bytes.Repeat([]byte("a"), 10000)
+	r := readerFunc(bytes.NewReader(data).Read)
+	n, err := w.ReadFromLimit(r, 5000)
+	assert.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, n, 5000)
+	assert.Equal(t, w.Len(), 5001)
+}
+
+func TestWriterReadFromLimitNoSize(t *testing.T) {
+	var w Writer
+	data := bytes.Repeat([]byte("a"), 10000)
+	r := readerFunc(bytes.NewReader(data).Read)
+	n, err := w.ReadFromLimit(r, 10000)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10000)
+	assert.BytesEqual(t, w, data)
+}
+
+func TestWriterReadFromLimitLimitedReader(t *testing.T) {
+	var w Writer
+	data := bytes.Repeat([]byte("a"), 10000)
+	br := bytes.NewReader(data)
+	r := &io.LimitedReader{
+		R: readerFunc(br.Read),
+		N: 6000,
+	}
+	n, err := w.ReadFromLimit(r, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 6000)
+	assert.AllocsPerRun(t, 10, func() {
+		w = nil
+		br.Reset(data)
+		r.N = 6000
+		_, _ = w.ReadFromLimit(r, 8000)
+	}, 2) // The upper bound is not preallocated.
+}
+
+func TestWriterReadFromLimitLimitedReaderNotPreallocated(t *testing.T) {
+	var w Writer
+	done := false
+	r := readerFunc(func(p []byte) (n int, err error) {
+		if done {
+			return 0, io.EOF
+		}
+		done = true
+		return copy(p, "ab"), nil
+	})
+	n, err := w.ReadFromLimit(io.LimitReader(r, 1<<28), 1<<28)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 2)
+	assert.BytesEqual(t, w, []byte("ab"))
+	assert.LessOrEqual(t, w.Cap(), readFromMinReadTest)
+}
+
+// readFromMinReadTest is the minimum read size of [Writer.ReadFrom].
+const readFromMinReadTest = 4096
+
+func TestWriterReadFromLimitFile(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 10000)
+	fp := filepath.Join(t.TempDir(), "file")
+	err := os.WriteFile(fp, data, 0o600)
+	assert.NoError(t, err)
+	f, err := os.Open(fp)
+	assert.NoError(t, err)
+	defer f.Close() //nolint:errcheck // Not needed.
+	var w Writer
+	n, err := w.ReadFromLimit(f, 20000)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 10000)
+	assert.BytesEqual(t, w, data)
+}
+
+func TestWriterReadFromLimitFileOffset(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 10000)
+	fp := filepath.Join(t.TempDir(), "file")
+	err := os.WriteFile(fp, data, 0o600)
+	assert.NoError(t, err)
+	f, err := os.Open(fp)
+	assert.NoError(t, err)
+	defer f.Close() //nolint:errcheck // Not needed.
+	_, err = f.Seek(4000, io.SeekStart)
+	assert.NoError(t, err)
+	var w Writer
+	n, err := w.ReadFromLimit(f, 20000)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 6000)
+	assert.BytesEqual(t, w, data[4000:])
+	assert.Less(t, w.Cap(), 10000)
+}
+
+func TestWriterReadFromLimitPanicNegative(t *testing.T) {
+	var w Writer
+	assert.Panics(t, func() {
+		_, _ = w.ReadFromLimit(bytes.NewReader(nil), -1)
+	})
+}
+
+func BenchmarkWriterReadFromLimit(b *testing.B) {
+	var w Writer
+	r := bytes.NewReader([]byte("abc"))
+	for b.Loop() {
+		_, _ = w.ReadFromLimit(r, 10)
+		w.Reset()
+		_, _ = r.Seek(0, io.SeekStart)
+	}
+}
+
 func TestWriterReset(t *testing.T) {
 	w := Writer("abc")
 	w.Reset()
@@ -0,0 +1,147 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/pierrre/go-libs/goroutine"
+	"github.com/pierrre/go-libs/syncutil"
+)
+
+// Batch returns a [iter.Seq] of batches of values received from a channel.
+//
+// A batch is yielded when it contains maxSize values, or when its oldest value has waited for maxWait.
+// The maxSize parameter is enforced to be at minimum 1.
+// If maxWait is <= 0, batches are only yielded when they are full.
+//
+// The iteration stops when the channel is closed or the [context.Context] is done.
+// In both cases, the last partial batch is yielded.
+//
+// By default, a new slice is allocated for each batch, and the caller can keep it.
+// See [WithBatchPool] to recycle slices.
+func Batch[E any](ctx context.Context, ch <-chan E, maxSize int, maxWait time.Duration, opts ...BatchOption[E]) iter.Seq[[]E] {
+	maxSize = max(maxSize, 1) // We need at least 1 value per batch.
+	o := buildBatchOptions(opts...)
+	return func(yield func([]E) bool) {
+		b := &batcher[E]{
+			maxSize: maxSize,
+			maxWait: maxWait,
+			options: o,
+			yield:   yield,
+		}
+		b.run(ctx, ch)
+	}
+}
+
+// BatchIter is like [Batch] for a [iter.Seq].
+//
+// The input [iter.Seq] is consumed in a separate goroutine.
+// If the caller stops iterating, the input iteration is stopped.
+func BatchIter[E any](ctx context.Context, seq iter.Seq[E], maxSize int, maxWait time.Duration, opts ...BatchOption[E]) iter.Seq[[]E] {
+	return func(yield func([]E) bool) {
+		ctx, cancel := context.WithCancel(ctx) //nolint:govet // Shadowing is expected here.
+		defer cancel()
+		ch := make(chan E)
+		defer goroutine.Start(ctx, func(ctx context.Context) {
+			defer close(ch)
+			_ = CollectTo(ctx, seq, ch)
+		}).Wait() // Wait until the producer is stopped.
+		defer cancel() // Notify the producer to stop.
+		// The batches are consumed until the producer closes the channel, so the values already sent are not lost.
+		Batch(context.WithoutCancel(ctx), ch, maxSize, maxWait, opts...)(yield)
+	}
+}
+
+type batcher[E any] struct {
+	maxSize int
+	maxWait time.Duration
+	options *batchOptions[E]
+	yield   func([]E) bool
+	batch   []E
+	timer   lazyTimer
+}
+
+func (b *batcher[E]) run(ctx context.Context, ch <-chan E) {
+	defer b.timer.stop()
+	done := ctx.Done()
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				b.flush()
+				return
+			}
+			if !b.add(e) {
+				return
+			}
+		case <-b.timer.c():
+			b.timer.fired()
+			if !b.flush() {
+				return
+			}
+		case <-done:
+			b.flush()
+			return
+		}
+	}
+}
+
+func (b *batcher[E]) add(e E) bool {
+	if b.batch == nil {
+		b.batch = b.newBatch()
+	}
+	b.batch = append(b.batch, e)
+	if len(b.batch) >= b.maxSize {
+		return b.flush()
+	}
+	if len(b.batch) == 1 && b.maxWait > 0 {
+		b.timer.start(b.maxWait)
+	}
+	return true
+}
+
+func (b *batcher[E]) flush() bool {
+	b.timer.stop()
+	if len(b.batch) == 0 {
+		return true
+	}
+	batch := b.batch
+	b.batch = nil
+	return b.yield(batch)
+}
+
+func (b *batcher[E]) newBatch() []E {
+	if b.options.pool != nil {
+		batch := b.options.pool.Get()
+		if batch != nil {
+			return batch[:0]
+		}
+	}
+	return make([]E, 0, b.maxSize)
+}
+
+type batchOptions[E any] struct {
+	pool *syncutil.ValuePool[[]E]
+}
+
+func buildBatchOptions[E any](opts ...BatchOption[E]) *batchOptions[E] {
+	o := &batchOptions[E]{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// BatchOption is an option for [Batch] and [BatchIter].
+type BatchOption[E any] func(*batchOptions[E])
+
+// WithBatchPool sets a pool used to get the batch slices.
+//
+// The caller should put the slices back to the pool once they are processed.
+// The slices retrieved from the pool are reset to length 0.
+func WithBatchPool[E any](p *syncutil.ValuePool[[]E]) BatchOption[E] {
+	return func(o *batchOptions[E]) {
+		o.pool = p
+	}
+}
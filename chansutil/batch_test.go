@@ -0,0 +1,193 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/syncutil"
+)
+
+func ExampleBatch() {
+	ctx := context.Background()
+	ch := make(chan int, 5)
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+	for batch := range Batch(ctx, ch, 2, time.Second) {
+		fmt.Println(batch)
+	}
+	// Output:
+	// [0 1]
+	// [2 3]
+	// [4]
+}
+
+func TestBatchSize(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for i := range 10 {
+				ch <- i
+			}
+		}()
+		res := slices.Collect(Batch(ctx, ch, 3, 0))
+		assert.DeepEqual(t, res, [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, {9}})
+	})
+}
+
+func TestBatchWait(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			ch <- 0
+			ch <- 1
+			time.Sleep(2 * time.Second)
+			ch <- 2
+			time.Sleep(500 * time.Millisecond)
+			ch <- 3
+			time.Sleep(700 * time.Millisecond)
+			ch <- 4
+		}()
+		start := time.Now()
+		var res [][]int
+		var times []time.Duration
+		for batch := range Batch(ctx, ch, 10, time.Second) {
+			res = append(res, batch)
+			times = append(times, time.Since(start))
+		}
+		assert.DeepEqual(t, res, [][]int{{0, 1}, {2, 3}, {4}})
+		assert.DeepEqual(t, times, []time.Duration{1 * time.Second, 3 * time.Second, 3200 * time.Millisecond})
+	})
+}
+
+func TestBatchMinSize(t *testing.T) {
+	ctx := t.Context()
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	res := slices.Collect(Batch(ctx, ch, 0, 0))
+	assert.DeepEqual(t, res, [][]int{{1}, {2}})
+}
+
+func TestBatchContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := make(chan int)
+		go func() {
+			ch <- 1
+			ch <- 2
+			cancel()
+		}()
+		res := slices.Collect(Batch(ctx, ch, 10, time.Hour))
+		assert.DeepEqual(t, res, [][]int{{1, 2}})
+	})
+}
+
+func TestBatchBreak(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int, 10)
+		for i := range 10 {
+			ch <- i
+		}
+		for batch := range Batch(ctx, ch, 2, time.Second) {
+			assert.SliceEqual(t, batch, []int{0, 1})
+			break
+		}
+	})
+}
+
+func TestBatchPool(t *testing.T) {
+	ctx := t.Context()
+	pool := &syncutil.ValuePool[[]int]{}
+	pool.Put(make([]int, 5, 10))
+	ch := make(chan int, 3)
+	for i := range 3 {
+		ch <- i
+	}
+	close(ch)
+	var res [][]int
+	for batch := range Batch(ctx, ch, 2, 0, WithBatchPool(pool)) {
+		res = append(res, slices.Clone(batch))
+		pool.Put(batch)
+	}
+	assert.DeepEqual(t, res, [][]int{{0, 1}, {2}})
+}
+
+func BenchmarkBatch(b *testing.B) {
+	ctx := b.Context()
+	pool := &syncutil.ValuePool[[]int]{}
+	ch := make(chan int, 100)
+	for b.Loop() {
+		for i := range 100 {
+			ch <- i
+		}
+		for batch := range Batch(ctx, ch, 10, time.Second, WithBatchPool(pool)) {
+			pool.Put(batch)
+			if batch[len(batch)-1] == 99 {
+				break
+			}
+		}
+	}
+}
+
+func TestBatchIter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		res := slices.Collect(BatchIter(ctx, slices.Values([]int{0, 1, 2, 3, 4}), 2, time.Second))
+		assert.DeepEqual(t, res, [][]int{{0, 1}, {2, 3}, {4}})
+	})
+}
+
+func TestBatchIterBreak(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		seq := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		for batch := range BatchIter(ctx, seq, 2, time.Second) {
+			assert.SliceEqual(t, batch, []int{0, 1})
+			break
+		}
+	})
+}
+
+func TestBatchIterContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		seq := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if i == 3 {
+					cancel()
+					time.Sleep(time.Second)
+				}
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		var res []int
+		for batch := range BatchIter(ctx, seq, 10, time.Hour) {
+			res = append(res, batch...)
+		}
+		// The value sent concurrently with the cancellation may be received.
+		assert.GreaterOrEqual(t, len(res), 3)
+		assert.LessOrEqual(t, len(res), 4)
+		assert.SliceEqual(t, res[:3], []int{0, 1, 2})
+	})
+}
@@ -0,0 +1,194 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// Broadcaster sends values to multiple subscribers.
+//
+// Each [Subscription] receives all values sent after it was created.
+// The behavior with slow subscribers is defined by Policy.
+//
+// It is safe for concurrent use.
+// The zero value is ready to use.
+type Broadcaster[E any] struct {
+	// Policy defines the behavior when a subscriber's buffer is full.
+	// The default value is [PolicyBlock].
+	// It must not be changed after the broadcaster is used.
+	Policy Policy
+
+	mu     sync.Mutex
+	subs   map[*Subscription[E]]struct{}
+	closed bool
+
+	// stopMu protects a copy of the subscriptions, that allows Close to unblock a pending Send without acquiring mu.
+	stopMu  sync.Mutex
+	stops   map[*Subscription[E]]struct{}
+	closing bool
+}
+
+// Subscribe creates a new [Subscription] with the given buffer size.
+//
+// The subscription is automatically unsubscribed when the [context.Context] is done.
+// If the broadcaster is closed, the returned subscription is already closed.
+func (b *Broadcaster[E]) Subscribe(ctx context.Context, buffer int) *Subscription[E] {
+	s := &Subscription[E]{
+		b:    b,
+		ch:   make(chan E, buffer),
+		stop: make(chan struct{}),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || !b.addStop(s) {
+		s.closeLocked()
+		return s
+	}
+	if b.subs == nil {
+		b.subs = make(map[*Subscription[E]]struct{})
+	}
+	b.subs[s] = struct{}{}
+	s.stopAfter = context.AfterFunc(ctx, s.Unsubscribe)
+	return s
+}
+
+// Send sends a value to all subscribers, according to the Policy.
+//
+// With [PolicyBlock], it waits until all subscribers have received the value, or are unsubscribed.
+// If the [context.Context] is done, it stops and returns the context error.
+// The remaining subscribers don't receive the value.
+func (b *Broadcaster[E]) Send(ctx context.Context, e E) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs {
+		switch sendWithPolicy(ctx, s.ch, e, b.Policy, s.stop) {
+		case sendOK:
+		case sendDropped:
+			s.dropped.Add(1)
+		case sendDisconnect:
+			s.dropped.Add(1)
+			s.closeLocked()
+		case sendStopped:
+			if ctx.Err() != nil {
+				return context.Cause(ctx) //nolint:wrapcheck // We want to return the original context error.
+			}
+		}
+	}
+	return nil
+}
+
+// Len returns the number of subscribers.
+func (b *Broadcaster[E]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Close unsubscribes all subscribers.
+//
+// Following calls to [Broadcaster.Subscribe] return closed subscriptions.
+func (b *Broadcaster[E]) Close() {
+	b.closeStops() // Unblock a pending Send, before acquiring the lock.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subs {
+		s.closeLocked()
+	}
+}
+
+// addStop registers the subscription for [Broadcaster.closeStops].
+// It returns false if the broadcaster is closing.
+func (b *Broadcaster[E]) addStop(s *Subscription[E]) bool {
+	b.stopMu.Lock()
+	defer b.stopMu.Unlock()
+	if b.closing {
+		return false
+	}
+	if b.stops == nil {
+		b.stops = make(map[*Subscription[E]]struct{})
+	}
+	b.stops[s] = struct{}{}
+	return true
+}
+
+func (b *Broadcaster[E]) removeStop(s *Subscription[E]) {
+	b.stopMu.Lock()
+	defer b.stopMu.Unlock()
+	delete(b.stops, s)
+}
+
+func (b *Broadcaster[E]) closeStops() {
+	b.stopMu.Lock()
+	defer b.stopMu.Unlock()
+	b.closing = true
+	for s := range b.stops {
+		s.closeStop()
+	}
+}
+
+// Subscription is a subscription to a [Broadcaster].
+//
+// It must be created with [Broadcaster.Subscribe].
+type Subscription[E any] struct {
+	b         *Broadcaster[E]
+	ch        chan E
+	stop      chan struct{}
+	stopOnce  sync.Once
+	stopAfter func() bool
+	closed    bool // Protected by the broadcaster lock.
+	dropped   atomic.Uint64
+}
+
+// C returns the channel that receives the values.
+//
+// It is closed when the subscription is unsubscribed.
+func (s *Subscription[E]) C() <-chan E {
+	return s.ch
+}
+
+// All returns an [iter.Seq] of the received values.
+//
+// See [Iter].
+func (s *Subscription[E]) All() iter.Seq[E] {
+	return Iter(s.ch)
+}
+
+// Dropped returns the number of values that were not delivered to this subscriber because of the Policy.
+func (s *Subscription[E]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe unsubscribes from the [Broadcaster], and closes the channel.
+//
+// It is safe to call it multiple times.
+func (s *Subscription[E]) Unsubscribe() {
+	s.closeStop() // Unblock a pending Send, before acquiring the lock.
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *Subscription[E]) closeStop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// closeLocked closes the subscription.
+// The broadcaster lock must be held.
+func (s *Subscription[E]) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.b.subs, s)
+	s.b.removeStop(s)
+	s.closeStop()
+	if s.stopAfter != nil {
+		s.stopAfter()
+	}
+	close(s.ch)
+}
@@ -0,0 +1,197 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleBroadcaster() {
+	ctx := context.Background()
+	var b Broadcaster[int]
+	s1 := b.Subscribe(ctx, 3)
+	s2 := b.Subscribe(ctx, 3)
+	for i := range 3 {
+		_ = b.Send(ctx, i)
+	}
+	b.Close()
+	for e := range s1.All() {
+		fmt.Println("s1:", e)
+	}
+	for e := range s2.All() {
+		fmt.Println("s2:", e)
+	}
+	// Output:
+	// s1: 0
+	// s1: 1
+	// s1: 2
+	// s2: 0
+	// s2: 1
+	// s2: 2
+}
+
+func TestBroadcasterBlock(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var b Broadcaster[int]
+		var wg sync.WaitGroup
+		results := make([][]int, 3)
+		for i := range results {
+			s := b.Subscribe(ctx, 0)
+			wg.Go(func() {
+				results[i] = slices.Collect(s.All())
+			})
+		}
+		assert.Equal(t, b.Len(), 3)
+		for i := range 10 {
+			err := b.Send(ctx, i)
+			assert.NoError(t, err)
+		}
+		b.Close()
+		wg.Wait()
+		for _, res := range results {
+			assert.SliceEqual(t, res, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+		}
+		assert.Equal(t, b.Len(), 0)
+	})
+}
+
+func TestBroadcasterBlockContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var b Broadcaster[int]
+		b.Subscribe(t.Context(), 0)
+		ctx, cancel := context.WithCancel(t.Context())
+		go func() {
+			cancel()
+		}()
+		err := b.Send(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+		b.Close()
+	})
+}
+
+func TestBroadcasterBlockClose(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var b Broadcaster[int]
+		b.Subscribe(ctx, 0) // Stalled.
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- b.Send(ctx, 1)
+		}()
+		synctest.Wait()
+		b.Close()
+		assert.NoError(t, <-errCh)
+		assert.Equal(t, b.Len(), 0)
+	})
+}
+
+func TestBroadcasterBlockUnsubscribe(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var b Broadcaster[int]
+		s := b.Subscribe(ctx, 0)
+		go func() {
+			s.Unsubscribe()
+		}()
+		err := b.Send(ctx, 1)
+		assert.NoError(t, err)
+		synctest.Wait()
+		assert.Equal(t, b.Len(), 0)
+		_, ok := <-s.C()
+		assert.False(t, ok)
+		s.Unsubscribe()
+	})
+}
+
+func TestBroadcasterSubscribeContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var b Broadcaster[int]
+		ctx, cancel := context.WithCancel(t.Context())
+		s := b.Subscribe(ctx, 0)
+		assert.Equal(t, b.Len(), 1)
+		cancel()
+		synctest.Wait()
+		assert.Equal(t, b.Len(), 0)
+		_, ok := <-s.C()
+		assert.False(t, ok)
+	})
+}
+
+func TestBroadcasterDropNewest(t *testing.T) {
+	ctx := t.Context()
+	b := &Broadcaster[int]{
+		Policy: PolicyDropNewest,
+	}
+	s := b.Subscribe(ctx, 2)
+	for i := range 5 {
+		err := b.Send(ctx, i)
+		assert.NoError(t, err)
+	}
+	b.Close()
+	assert.SliceEqual(t, slices.Collect(s.All()), []int{0, 1})
+	assert.Equal(t, s.Dropped(), 3)
+}
+
+func TestBroadcasterDropOldest(t *testing.T) {
+	ctx := t.Context()
+	b := &Broadcaster[int]{
+		Policy: PolicyDropOldest,
+	}
+	s := b.Subscribe(ctx, 2)
+	for i := range 5 {
+		err := b.Send(ctx, i)
+		assert.NoError(t, err)
+	}
+	b.Close()
+	assert.SliceEqual(t, slices.Collect(s.All()), []int{3, 4})
+	assert.Equal(t, s.Dropped(), 3)
+}
+
+func TestBroadcasterDisconnect(t *testing.T) {
+	ctx := t.Context()
+	b := &Broadcaster[int]{
+		Policy: PolicyDisconnect,
+	}
+	s1 := b.Subscribe(ctx, 2)
+	s2 := b.Subscribe(ctx, 10)
+	for i := range 5 {
+		err := b.Send(ctx, i)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, b.Len(), 1)
+	assert.SliceEqual(t, slices.Collect(s1.All()), []int{0, 1})
+	assert.Equal(t, s1.Dropped(), 1)
+	b.Close()
+	assert.SliceEqual(t, slices.Collect(s2.All()), []int{0, 1, 2, 3, 4})
+}
+
+func TestBroadcasterClosed(t *testing.T) {
+	ctx := t.Context()
+	var b Broadcaster[int]
+	b.Close()
+	s := b.Subscribe(ctx, 1)
+	_, ok := <-s.C()
+	assert.False(t, ok)
+	err := b.Send(ctx, 1)
+	assert.NoError(t, err)
+	s.Unsubscribe()
+}
+
+func BenchmarkBroadcaster(b *testing.B) {
+	ctx := b.Context()
+	br := &Broadcaster[int]{
+		Policy: PolicyDropOldest,
+	}
+	for range 10 {
+		br.Subscribe(ctx, 1)
+	}
+	for b.Loop() {
+		_ = br.Send(ctx, 1)
+	}
+}
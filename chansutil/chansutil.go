@@ -17,16 +17,46 @@ func Iter[C ~chan E, E any](ch C) iter.Seq[E] {
 	}
 }
 
+// IterContext is like [Iter], but it stops when the [context.Context] is done.
+func IterContext[C ~chan E, E any](ctx context.Context, ch C) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		done := ctx.Done()
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok || !yield(e) {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
 // CollectTo collects elements from an [iter.Seq] and sends them to a channel.
 // If the context is cancelled, it stops collecting and returns the context error.
 func CollectTo[E any](ctx context.Context, it iter.Seq[E], ch chan<- E) error {
 	done := ctx.Done()
-	var err error
-	it(func(e E) bool {
+	return collect(ctx, it, func(e E) bool {
 		select {
 		case ch <- e:
 		case <-done:
 		}
+		return true
+	})
+}
+
+// collect calls send for each element of an [iter.Seq].
+// It stops if send returns false.
+// If the context is cancelled, it stops collecting and returns the context error.
+func collect[E any](ctx context.Context, it iter.Seq[E], send func(e E) bool) error {
+	done := ctx.Done()
+	var err error
+	it(func(e E) bool {
+		if !send(e) {
+			return false
+		}
 		select {
 		case <-done:
 			err = context.Cause(ctx)
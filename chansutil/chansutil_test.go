@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 	"testing"
+	"testing/synctest"
 
 	"github.com/pierrre/assert"
 )
@@ -24,6 +25,36 @@ func TestIter(t *testing.T) {
 	assert.SliceEqual(t, res, expected)
 }
 
+func TestIterContext(t *testing.T) {
+	ctx := t.Context()
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	it := IterContext(ctx, ch)
+	for e := range it {
+		assert.Equal(t, e, 1)
+		break
+	}
+	res := slices.Collect(it)
+	expected := []int{2, 3}
+	assert.SliceEqual(t, res, expected)
+}
+
+func TestIterContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := make(chan int)
+		go func() {
+			ch <- 1
+			cancel()
+		}()
+		res := slices.Collect(IterContext(ctx, ch))
+		assert.SliceEqual(t, res, []int{1})
+	})
+}
+
 func TestCollectTo(t *testing.T) {
 	ctx := context.Background()
 	vs := []int{1, 2, 3}
@@ -0,0 +1,46 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Debounce returns a [iter.Seq] that yields the last value received from a channel, once no value has been received for the quiet duration.
+//
+// If the channel is closed, the pending value is yielded immediately, and the iteration stops.
+// If the [context.Context] is done, the pending value is dropped, and the iteration stops.
+func Debounce[E any](ctx context.Context, ch <-chan E, quiet time.Duration) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		var timer lazyTimer
+		defer timer.stop()
+		var pending E
+		hasPending := false
+		done := ctx.Done()
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					if hasPending {
+						yield(pending)
+					}
+					return
+				}
+				pending = e
+				hasPending = true
+				timer.start(quiet)
+			case <-timer.c():
+				timer.fired()
+				e := pending
+				var zero E
+				pending = zero // Allow GC.
+				hasPending = false
+				if !yield(e) {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}
+}
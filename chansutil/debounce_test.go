@@ -0,0 +1,110 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleDebounce() {
+	ctx := context.Background()
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	for e := range Debounce(ctx, ch, time.Second) {
+		fmt.Println(e)
+	}
+	// Output:
+	// 3
+}
+
+type timedValue[E any] struct {
+	value E
+	time  time.Duration
+}
+
+func sendTimed[E any](ch chan<- E, values []timedValue[E]) {
+	defer close(ch)
+	start := time.Now()
+	for _, v := range values {
+		time.Sleep(v.time - time.Since(start))
+		ch <- v.value
+	}
+}
+
+func collectTimed[E any](seq func(yield func(E) bool)) []timedValue[E] {
+	start := time.Now()
+	var res []timedValue[E]
+	for e := range seq {
+		res = append(res, timedValue[E]{value: e, time: time.Since(start)})
+	}
+	return res
+}
+
+func TestDebounce(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int)
+		go sendTimed(ch, []timedValue[int]{
+			{0, 0},
+			{1, 500 * time.Millisecond},
+			{2, 900 * time.Millisecond},
+			{3, 3 * time.Second},
+			{4, 5 * time.Second},
+			{5, 5500 * time.Millisecond},
+		})
+		res := collectTimed(Debounce(ctx, ch, time.Second))
+		assert.SliceEqual(t, res, []timedValue[int]{
+			{2, 1900 * time.Millisecond},
+			{3, 4 * time.Second},
+			{5, 5500 * time.Millisecond},
+		})
+	})
+}
+
+func TestDebounceContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := make(chan int)
+		go func() {
+			ch <- 1
+			cancel()
+		}()
+		var res []int
+		for e := range Debounce(ctx, ch, time.Second) {
+			res = append(res, e)
+		}
+		assert.SliceEmpty(t, res)
+	})
+}
+
+func TestDebounceBreak(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int, 1)
+		ch <- 1
+		for e := range Debounce(ctx, ch, time.Second) {
+			assert.Equal(t, e, 1)
+			break
+		}
+	})
+}
+
+func BenchmarkDebounce(b *testing.B) {
+	ctx := b.Context()
+	for b.Loop() {
+		ch := make(chan int, 100)
+		for i := range 100 {
+			ch <- i
+		}
+		close(ch)
+		for range Debounce(ctx, ch, time.Second) {
+		}
+	}
+}
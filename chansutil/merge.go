@@ -0,0 +1,66 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+	"sync/atomic"
+
+	"github.com/pierrre/go-libs/goroutine"
+)
+
+// Merge returns a [iter.Seq] that yields the values received from all channels, as they arrive.
+//
+// It starts a goroutine per channel.
+// The iteration stops when all channels are closed, when the [context.Context] is done, or when the caller stops iterating.
+// In all cases, the goroutines are stopped before the iteration returns.
+func Merge[E any](ctx context.Context, chans ...<-chan E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if len(chans) == 0 {
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx) //nolint:govet // Shadowing is expected here.
+		defer cancel()
+		out := make(chan E)
+		running := int64(len(chans))
+		defer goroutine.StartN(ctx, len(chans), func(ctx context.Context, i int) {
+			defer func() {
+				if atomic.AddInt64(&running, -1) == 0 {
+					close(out) // Notify the consumer that there are no more values.
+				}
+			}()
+			forward(ctx, chans[i], out)
+		}).Wait() // Wait until the goroutines are stopped.
+		defer cancel() // Notify the goroutines to stop.
+		done := ctx.Done()
+		for {
+			select {
+			case e, ok := <-out:
+				if !ok || !yield(e) {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// forward sends the values received from in to out, until in is closed or the context is done.
+func forward[E any](ctx context.Context, in <-chan E, out chan<- E) {
+	done := ctx.Done()
+	for {
+		select {
+		case e, ok := <-in:
+			if !ok {
+				return
+			}
+			select {
+			case out <- e:
+			case <-done:
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/synctest"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleMerge() {
+	ctx := context.Background()
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	go func() {
+		defer close(ch1)
+		ch1 <- 1
+		ch1 <- 2
+	}()
+	go func() {
+		defer close(ch2)
+		ch2 <- 3
+	}()
+	for e := range Merge(ctx, ch1, ch2) {
+		fmt.Println(e)
+	}
+	// Unordered output:
+	// 1
+	// 2
+	// 3
+}
+
+func TestMerge(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		chans := make([]<-chan int, 3)
+		for i := range chans {
+			ch := make(chan int)
+			chans[i] = ch
+			go func() {
+				defer close(ch)
+				for j := range 10 {
+					ch <- i*10 + j
+				}
+			}()
+		}
+		res := slices.Collect(Merge(ctx, chans...))
+		slices.Sort(res)
+		expected := make([]int, 30)
+		for i := range expected {
+			expected[i] = i
+		}
+		assert.SliceEqual(t, res, expected)
+	})
+}
+
+func TestMergeEmpty(t *testing.T) {
+	ctx := t.Context()
+	res := slices.Collect(Merge[int](ctx))
+	assert.SliceEmpty(t, res)
+}
+
+func TestMergeBreak(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch1 := make(chan int)
+		ch2 := make(chan int)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case ch1 <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		for e := range Merge(ctx, ch1, ch2) {
+			assert.Equal(t, e, 0)
+			break
+		}
+	})
+}
+
+func TestMergeContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch1 := make(chan int)
+		ch2 := make(chan int)
+		go func() {
+			ch1 <- 1
+			cancel()
+		}()
+		res := slices.Collect(Merge(ctx, ch1, ch2))
+		assert.LessOrEqual(t, len(res), 1)
+	})
+}
+
+func BenchmarkMerge(b *testing.B) {
+	ctx := b.Context()
+	for b.Loop() {
+		ch1 := make(chan int, 10)
+		ch2 := make(chan int, 10)
+		for i := range 10 {
+			ch1 <- i
+			ch2 <- i
+		}
+		close(ch1)
+		close(ch2)
+		for range Merge(ctx, ch1, ch2) {
+		}
+	}
+}
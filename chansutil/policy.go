@@ -0,0 +1,64 @@
+package chansutil
+
+import (
+	"context"
+)
+
+// Policy defines the behavior when sending a value to a slow receiver (the channel buffer is full).
+type Policy int
+
+const (
+	// PolicyBlock waits until the receiver is ready.
+	PolicyBlock Policy = iota
+	// PolicyDropNewest drops the value being sent.
+	PolicyDropNewest
+	// PolicyDropOldest drops the oldest value in the channel buffer, and sends the new value.
+	// If the channel is unbuffered, it behaves like [PolicyDropNewest].
+	PolicyDropOldest
+	// PolicyDisconnect disconnects the receiver (its channel is closed).
+	PolicyDisconnect
+)
+
+type sendResult int
+
+const (
+	sendOK sendResult = iota
+	sendDropped
+	sendDisconnect
+	sendStopped
+)
+
+// sendWithPolicy sends e to ch according to the [Policy].
+//
+// It must be the only sender of ch.
+// With [PolicyBlock], it returns sendStopped if the context or stop is done.
+func sendWithPolicy[E any](ctx context.Context, ch chan E, e E, p Policy, stop <-chan struct{}) sendResult {
+	select {
+	case ch <- e:
+		return sendOK
+	default:
+	}
+	switch p {
+	case PolicyBlock:
+		select {
+		case ch <- e:
+			return sendOK
+		case <-stop:
+		case <-ctx.Done():
+		}
+		return sendStopped
+	case PolicyDropNewest:
+	case PolicyDropOldest:
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	case PolicyDisconnect:
+		return sendDisconnect
+	}
+	return sendDropped
+}
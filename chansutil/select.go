@@ -0,0 +1,127 @@
+package chansutil
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ErrSelectTimeout is returned by [Selector.Select] when the timeout expires.
+var ErrSelectTimeout = errors.New("select timeout")
+
+// ErrSelectEmpty is returned by [Selector.Select] when there is no remaining channel.
+var ErrSelectEmpty = errors.New("select empty")
+
+// Selector receives values from a dynamic list of channels.
+//
+// Closed channels are automatically removed from the following calls to [Selector.Select].
+//
+// It is not safe for concurrent use.
+// It must be created with [NewSelector].
+type Selector[E any] struct {
+	cases   []reflect.SelectCase
+	indexes []int
+	timer   *time.Timer
+}
+
+const (
+	selectCaseDone = iota
+	selectCaseTimer
+	selectCasesFixed
+)
+
+// NewSelector creates a new [Selector] for the given channels.
+func NewSelector[E any](chans ...<-chan E) *Selector[E] {
+	s := &Selector[E]{
+		cases:   make([]reflect.SelectCase, selectCasesFixed, selectCasesFixed+len(chans)),
+		indexes: make([]int, len(chans)),
+	}
+	for i := range selectCasesFixed {
+		s.cases[i].Dir = reflect.SelectRecv
+	}
+	for i, ch := range chans {
+		s.cases = append(s.cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(ch),
+		})
+		s.indexes[i] = i
+	}
+	return s
+}
+
+// Len returns the number of remaining channels.
+func (s *Selector[E]) Len() int {
+	return len(s.indexes)
+}
+
+// SelectResult is the result of [Selector.Select].
+type SelectResult[E any] struct {
+	// Index is the index of the channel, in the list given to [NewSelector].
+	Index int
+	// Value is the received value.
+	// It is the zero value if Closed is true.
+	Value E
+	// Closed is true if the channel is closed.
+	// The channel is removed from the following calls.
+	Closed bool
+}
+
+// Select waits until a value is received from one of the channels, or one of them is closed.
+//
+// If timeout is > 0, it returns [ErrSelectTimeout] when it expires.
+// If the [context.Context] is done, it returns the context error.
+// If there is no remaining channel, it returns [ErrSelectEmpty].
+func (s *Selector[E]) Select(ctx context.Context, timeout time.Duration) (SelectResult[E], error) {
+	if len(s.indexes) == 0 {
+		return SelectResult[E]{}, ErrSelectEmpty
+	}
+	s.setDone(ctx.Done())
+	defer s.setDone(nil)
+	if timeout > 0 {
+		s.startTimer(timeout)
+		defer s.stopTimer()
+	}
+	chosen, recv, ok := reflect.Select(s.cases)
+	switch chosen {
+	case selectCaseDone:
+		return SelectResult[E]{}, context.Cause(ctx) //nolint:wrapcheck // We want to return the original context error.
+	case selectCaseTimer:
+		return SelectResult[E]{}, ErrSelectTimeout
+	}
+	i := chosen - selectCasesFixed
+	res := SelectResult[E]{
+		Index: s.indexes[i],
+	}
+	if !ok {
+		res.Closed = true
+		s.cases = slices.Delete(s.cases, chosen, chosen+1)
+		s.indexes = slices.Delete(s.indexes, i, i+1)
+		return res, nil
+	}
+	res.Value, _ = recv.Interface().(E) // The type assertion can fail only if E is an interface and the value is nil.
+	return res, nil
+}
+
+func (s *Selector[E]) setDone(done <-chan struct{}) {
+	if done == nil {
+		s.cases[selectCaseDone].Chan = reflect.Value{}
+		return
+	}
+	s.cases[selectCaseDone].Chan = reflect.ValueOf(done)
+}
+
+func (s *Selector[E]) startTimer(timeout time.Duration) {
+	if s.timer == nil {
+		s.timer = time.NewTimer(timeout)
+	} else {
+		s.timer.Reset(timeout)
+	}
+	s.cases[selectCaseTimer].Chan = reflect.ValueOf(s.timer.C)
+}
+
+func (s *Selector[E]) stopTimer() {
+	s.timer.Stop()
+	s.cases[selectCaseTimer].Chan = reflect.Value{}
+}
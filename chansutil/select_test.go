@@ -0,0 +1,124 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleSelector() {
+	ctx := context.Background()
+	ch1 := make(chan int, 1)
+	ch2 := make(chan int, 1)
+	ch1 <- 1
+	close(ch1)
+	close(ch2)
+	s := NewSelector[int](ch1, ch2)
+	for s.Len() > 0 {
+		res, err := s.Select(ctx, 0)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%+v\n", res)
+	}
+	// Unordered output:
+	// {Index:0 Value:1 Closed:false}
+	// {Index:0 Value:0 Closed:true}
+	// {Index:1 Value:0 Closed:true}
+}
+
+func TestSelector(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		chs := make([]chan int, 3)
+		ins := make([]<-chan int, len(chs))
+		for i := range chs {
+			chs[i] = make(chan int)
+			ins[i] = chs[i]
+		}
+		s := NewSelector(ins...)
+		assert.Equal(t, s.Len(), 3)
+		for _, tc := range []struct {
+			action   func()
+			expected SelectResult[int]
+		}{
+			{func() { chs[1] <- 10 }, SelectResult[int]{Index: 1, Value: 10}},
+			{func() { close(chs[1]) }, SelectResult[int]{Index: 1, Closed: true}},
+			{func() { chs[2] <- 20 }, SelectResult[int]{Index: 2, Value: 20}},
+			{func() { close(chs[0]) }, SelectResult[int]{Index: 0, Closed: true}},
+			{func() { close(chs[2]) }, SelectResult[int]{Index: 2, Closed: true}},
+		} {
+			go tc.action()
+			res, err := s.Select(ctx, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, res, tc.expected)
+		}
+		assert.Equal(t, s.Len(), 0)
+		_, err := s.Select(ctx, 0)
+		assert.ErrorIs(t, err, ErrSelectEmpty)
+	})
+}
+
+func TestSelectorTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int)
+		s := NewSelector[int](ch)
+		start := time.Now()
+		_, err := s.Select(ctx, time.Second)
+		assert.ErrorIs(t, err, ErrSelectTimeout)
+		assert.Equal(t, time.Since(start), time.Second)
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			ch <- 1
+		}()
+		res, err := s.Select(ctx, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, res, SelectResult[int]{Index: 0, Value: 1})
+	})
+}
+
+func TestSelectorContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		s := NewSelector[int](make(chan int))
+		go func() {
+			time.Sleep(time.Second)
+			cancel()
+		}()
+		_, err := s.Select(ctx, 0)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, s.Len(), 1)
+	})
+}
+
+func TestSelectorInterfaceNil(t *testing.T) {
+	ctx := t.Context()
+	ch := make(chan error, 1)
+	ch <- nil
+	s := NewSelector[error](ch)
+	res, err := s.Select(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, res, SelectResult[error]{Index: 0})
+}
+
+func BenchmarkSelector(b *testing.B) {
+	ctx := b.Context()
+	chs := make([]<-chan int, 10)
+	ch := make(chan int, 1)
+	for i := range chs {
+		chs[i] = ch
+	}
+	s := NewSelector(chs...)
+	for b.Loop() {
+		ch <- 1
+		_, err := s.Select(ctx, time.Second)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
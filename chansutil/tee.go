@@ -0,0 +1,99 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+
+	"github.com/pierrre/go-libs/goroutine"
+)
+
+// Tee sends the values of a [iter.Seq] to n channels.
+//
+// It consumes the [iter.Seq] in a new goroutine.
+// The caller must call the returned [goroutine.Waiter], e.g. after the channels are consumed.
+// Each value is sent to all channels, in order.
+// The channels are created with the given buffer size.
+//
+// By default, the producer waits until all consumers have received the value, so a slow consumer slows down all consumers.
+// See [WithTeePolicy] to change this behavior.
+//
+// The channels are closed when the [iter.Seq] is exhausted, or when the [context.Context] is done.
+// See [WithTeeDone] to get the result.
+func Tee[E any](ctx context.Context, seq iter.Seq[E], n int, buffer int, opts ...TeeOption) ([]<-chan E, goroutine.Waiter) {
+	o := buildTeeOptions(opts...)
+	chans := make([]chan E, n)
+	res := make([]<-chan E, n)
+	for i := range chans {
+		chans[i] = make(chan E, buffer)
+		res[i] = chans[i]
+	}
+	w := goroutine.Start(ctx, func(ctx context.Context) {
+		err := tee(ctx, seq, chans, o.policy)
+		if o.done != nil {
+			o.done(err)
+		}
+	})
+	return res, w
+}
+
+func tee[E any](ctx context.Context, seq iter.Seq[E], chans []chan E, p Policy) error {
+	open := len(chans)
+	defer func() {
+		for _, ch := range chans {
+			if ch != nil {
+				close(ch)
+			}
+		}
+	}()
+	if open == 0 {
+		return nil
+	}
+	return collect(ctx, seq, func(e E) bool {
+		for i, ch := range chans {
+			if ch == nil {
+				continue
+			}
+			if sendWithPolicy(ctx, ch, e, p, nil) == sendDisconnect {
+				close(ch)
+				chans[i] = nil
+				open--
+			}
+		}
+		return open > 0 // Stop if all consumers are disconnected.
+	})
+}
+
+type teeOptions struct {
+	policy Policy
+	done   func(err error)
+}
+
+func buildTeeOptions(opts ...TeeOption) *teeOptions {
+	o := &teeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// TeeOption is an option for [Tee].
+type TeeOption func(*teeOptions)
+
+// WithTeePolicy sets the [Policy] used when a consumer is slow.
+//
+// The default value is [PolicyBlock].
+// With [PolicyDisconnect], the channel of the slow consumer is closed, and the producer stops when all consumers are disconnected.
+func WithTeePolicy(p Policy) TeeOption {
+	return func(o *teeOptions) {
+		o.policy = p
+	}
+}
+
+// WithTeeDone sets a function that is called when the producer stops, after the channels are closed.
+//
+// It receives the same error as [CollectTo]: nil if the [iter.Seq] is exhausted, or the context error.
+func WithTeeDone(f func(err error)) TeeOption {
+	return func(o *teeOptions) {
+		o.done = f
+	}
+}
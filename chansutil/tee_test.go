@@ -0,0 +1,159 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleTee() {
+	ctx := context.Background()
+	chans, w := Tee(ctx, slices.Values([]int{1, 2, 3}), 2, 3)
+	for i, ch := range chans {
+		for e := range ch {
+			fmt.Println(i, e)
+		}
+	}
+	w.Wait()
+	// Output:
+	// 0 1
+	// 0 2
+	// 0 3
+	// 1 1
+	// 1 2
+	// 1 3
+}
+
+func TestTee(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var doneErr error
+		doneCalled := false
+		chans, w := Tee(ctx, slices.Values([]int{0, 1, 2, 3, 4}), 3, 0, WithTeeDone(func(err error) {
+			doneErr = err
+			doneCalled = true
+		}))
+		assert.SliceLen(t, chans, 3)
+		results := collectChans(chans)
+		for _, res := range results {
+			assert.SliceEqual(t, res, []int{0, 1, 2, 3, 4})
+		}
+		w.Wait()
+		assert.True(t, doneCalled)
+		assert.NoError(t, doneErr)
+	})
+}
+
+func TestTeeZero(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		doneCalled := false
+		chans, w := Tee(ctx, slices.Values([]int{0, 1, 2}), 0, 0, WithTeeDone(func(err error) {
+			doneCalled = true
+		}))
+		assert.SliceEmpty(t, chans)
+		w.Wait()
+		assert.True(t, doneCalled)
+	})
+}
+
+func TestTeeBlock(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		chans, w := Tee(ctx, slices.Values([]int{0, 1, 2, 3, 4}), 2, 1)
+		synctest.Wait()
+		assert.Equal(t, len(chans[0]), 1)
+		assert.Equal(t, len(chans[1]), 1)
+		assert.Equal(t, <-chans[0], 0)
+		assert.Equal(t, <-chans[0], 1)
+		synctest.Wait()
+		assert.Equal(t, len(chans[0]), 0) // The producer is blocked by the second consumer.
+		results := collectChans(chans)
+		assert.SliceEqual(t, results[0], []int{2, 3, 4})
+		assert.SliceEqual(t, results[1], []int{0, 1, 2, 3, 4})
+		w.Wait()
+	})
+}
+
+func TestTeeDropNewest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		chans, w := Tee(ctx, slices.Values([]int{0, 1, 2, 3, 4}), 2, 2, WithTeePolicy(PolicyDropNewest))
+		synctest.Wait()
+		var res []int
+		for e := range chans[0] {
+			res = append(res, e)
+		}
+		assert.SliceEqual(t, res, []int{0, 1})
+		collectChans(chans)
+		w.Wait()
+	})
+}
+
+func TestTeeDisconnect(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		doneCalled := false
+		chans, w := Tee(ctx, slices.Values([]int{0, 1, 2, 3, 4}), 2, 2, WithTeePolicy(PolicyDisconnect), WithTeeDone(func(err error) {
+			doneCalled = true
+		}))
+		synctest.Wait()
+		assert.True(t, doneCalled) // All consumers are disconnected.
+		results := collectChans(chans)
+		assert.SliceEqual(t, results[0], []int{0, 1})
+		assert.SliceEqual(t, results[1], []int{0, 1})
+		w.Wait()
+	})
+}
+
+func TestTeeContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		var doneErr error
+		seq := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+		chans, w := Tee(ctx, seq, 2, 0, WithTeeDone(func(err error) {
+			doneErr = err
+		}))
+		assert.Equal(t, <-chans[0], 0)
+		assert.Equal(t, <-chans[1], 0)
+		cancel()
+		collectChans(chans)
+		w.Wait()
+		assert.ErrorIs(t, doneErr, context.Canceled)
+	})
+}
+
+func collectChans[E any](chans []<-chan E) [][]E {
+	results := make([][]E, len(chans))
+	var wg sync.WaitGroup
+	for i, ch := range chans {
+		wg.Go(func() {
+			for e := range ch {
+				results[i] = append(results[i], e)
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+func BenchmarkTee(b *testing.B) {
+	ctx := b.Context()
+	values := make([]int, 100)
+	for b.Loop() {
+		chans, w := Tee(ctx, slices.Values(values), 3, 10)
+		collectChans(chans)
+		w.Wait()
+	}
+}
@@ -0,0 +1,106 @@
+package chansutil
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// ThrottleEdge defines which values are yielded by [Throttle] during an interval.
+type ThrottleEdge int
+
+const (
+	// ThrottleLeading yields the first value of an interval, when it is received.
+	ThrottleLeading ThrottleEdge = 1 << iota
+	// ThrottleTrailing yields the last value of an interval, when it ends.
+	ThrottleTrailing
+)
+
+// Throttle returns a [iter.Seq] that yields at most one value received from a channel per interval.
+//
+// The edges parameter defines which values are yielded: [ThrottleLeading], [ThrottleTrailing], or both.
+// If it is 0, both are used.
+// The other values received during an interval are dropped.
+//
+// If the channel is closed, the pending trailing value is yielded immediately, and the iteration stops.
+// If the [context.Context] is done, the pending trailing value is dropped, and the iteration stops.
+func Throttle[E any](ctx context.Context, ch <-chan E, interval time.Duration, edges ThrottleEdge) iter.Seq[E] {
+	if edges == 0 {
+		edges = ThrottleLeading | ThrottleTrailing
+	}
+	return func(yield func(E) bool) {
+		t := &throttler[E]{
+			interval: interval,
+			leading:  edges&ThrottleLeading != 0,
+			trailing: edges&ThrottleTrailing != 0,
+			yield:    yield,
+		}
+		t.run(ctx, ch)
+	}
+}
+
+type throttler[E any] struct {
+	interval   time.Duration
+	leading    bool
+	trailing   bool
+	yield      func(E) bool
+	timer      lazyTimer
+	pending    E
+	hasPending bool
+}
+
+func (t *throttler[E]) run(ctx context.Context, ch <-chan E) {
+	defer t.timer.stop()
+	done := ctx.Done()
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				t.flush()
+				return
+			}
+			if !t.receive(e) {
+				return
+			}
+		case <-t.timer.c():
+			t.timer.fired()
+			if t.hasPending {
+				// The trailing value starts a new interval.
+				t.timer.start(t.interval)
+				if !t.flush() {
+					return
+				}
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (t *throttler[E]) receive(e E) bool {
+	if t.timer.running {
+		if t.trailing {
+			t.pending = e
+			t.hasPending = true
+		}
+		return true
+	}
+	t.timer.start(t.interval)
+	if t.leading {
+		return t.yield(e)
+	}
+	t.pending = e
+	t.hasPending = true
+	return true
+}
+
+func (t *throttler[E]) flush() bool {
+	if !t.hasPending {
+		return true
+	}
+	e := t.pending
+	var zero E
+	t.pending = zero // Allow GC.
+	t.hasPending = false
+	return t.yield(e)
+}
@@ -0,0 +1,125 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleThrottle() {
+	ctx := context.Background()
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	for e := range Throttle(ctx, ch, time.Second, ThrottleLeading|ThrottleTrailing) {
+		fmt.Println(e)
+	}
+	// Output:
+	// 1
+	// 3
+}
+
+var testThrottleValues = []timedValue[int]{
+	{0, 0},
+	{1, 300 * time.Millisecond},
+	{2, 600 * time.Millisecond},
+	{3, 1200 * time.Millisecond},
+	{4, 3500 * time.Millisecond},
+}
+
+func TestThrottle(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		edges    ThrottleEdge
+		expected []timedValue[int]
+	}{
+		{
+			name:  "Both",
+			edges: 0,
+			expected: []timedValue[int]{
+				{0, 0},
+				{2, time.Second},
+				{3, 2 * time.Second},
+				{4, 3500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "Leading",
+			edges: ThrottleLeading,
+			expected: []timedValue[int]{
+				{0, 0},
+				{3, 1200 * time.Millisecond},
+				{4, 3500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "Trailing",
+			edges: ThrottleTrailing,
+			expected: []timedValue[int]{
+				{2, time.Second},
+				{3, 2 * time.Second},
+				{4, 3500 * time.Millisecond},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				ctx := t.Context()
+				ch := make(chan int)
+				go sendTimed(ch, testThrottleValues)
+				res := collectTimed(Throttle(ctx, ch, time.Second, tc.edges))
+				assert.SliceEqual(t, res, tc.expected)
+			})
+		})
+	}
+}
+
+func TestThrottleContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := make(chan int)
+		go func() {
+			ch <- 1
+			ch <- 2
+			cancel()
+		}()
+		var res []int
+		for e := range Throttle(ctx, ch, time.Second, 0) {
+			res = append(res, e)
+		}
+		assert.SliceEqual(t, res, []int{1})
+	})
+}
+
+func TestThrottleBreak(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		ch := make(chan int, 3)
+		ch <- 1
+		ch <- 2
+		ch <- 3
+		for e := range Throttle(ctx, ch, time.Second, 0) {
+			assert.Equal(t, e, 1)
+			break
+		}
+	})
+}
+
+func BenchmarkThrottle(b *testing.B) {
+	ctx := b.Context()
+	for b.Loop() {
+		ch := make(chan int, 100)
+		for i := range 100 {
+			ch <- i
+		}
+		close(ch)
+		for range Throttle(ctx, ch, time.Second, 0) {
+		}
+	}
+}
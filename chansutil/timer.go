@@ -0,0 +1,41 @@
+package chansutil
+
+import (
+	"time"
+)
+
+// lazyTimer is a [time.Timer] that is allocated on first use.
+//
+// Its channel is nil when it is not running, so it can be used in a select statement.
+type lazyTimer struct {
+	t       *time.Timer
+	running bool
+}
+
+func (t *lazyTimer) start(d time.Duration) {
+	t.running = true
+	if t.t == nil {
+		t.t = time.NewTimer(d)
+		return
+	}
+	t.t.Reset(d)
+}
+
+func (t *lazyTimer) stop() {
+	t.running = false
+	if t.t != nil {
+		t.t.Stop()
+	}
+}
+
+// fired must be called after a value is received from the channel.
+func (t *lazyTimer) fired() {
+	t.running = false
+}
+
+func (t *lazyTimer) c() <-chan time.Time {
+	if !t.running {
+		return nil
+	}
+	return t.t.C
+}
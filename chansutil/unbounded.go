@@ -0,0 +1,197 @@
+package chansutil
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pierrre/go-libs/goroutine"
+)
+
+// Unbounded is a channel with an unbounded buffer.
+//
+// The values sent to [Unbounded.In] are stored in a growable queue, and delivered in order to [Unbounded.Out].
+// Sending to [Unbounded.In] never waits for the receiver.
+//
+// When [Unbounded.In] is closed, the remaining values are delivered, then [Unbounded.Out] is closed.
+// When the [context.Context] is done, [Unbounded.Out] is closed, the remaining values are dropped,
+// and the following values sent to [Unbounded.In] are discarded until it is closed.
+//
+// It must be created with [NewUnbounded].
+type Unbounded[E any] struct {
+	in      chan E
+	out     chan E
+	options *unboundedOptions
+	len     atomic.Int64
+	waiter  goroutine.Waiter
+}
+
+// NewUnbounded creates a new [Unbounded].
+//
+// It starts a goroutine that stops when [Unbounded.In] is closed.
+func NewUnbounded[E any](ctx context.Context, opts ...UnboundedOption) *Unbounded[E] {
+	u := &Unbounded[E]{
+		in:      make(chan E),
+		out:     make(chan E),
+		options: buildUnboundedOptions(opts...),
+	}
+	u.waiter = goroutine.Start(ctx, u.run)
+	return u
+}
+
+// In returns the send-side channel.
+//
+// The caller must close it once all values are sent.
+func (u *Unbounded[E]) In() chan<- E {
+	return u.in
+}
+
+// Out returns the receive-side channel.
+func (u *Unbounded[E]) Out() <-chan E {
+	return u.out
+}
+
+// Len returns the number of values in the queue.
+func (u *Unbounded[E]) Len() int {
+	return int(u.len.Load())
+}
+
+// Wait waits until the goroutine is stopped.
+func (u *Unbounded[E]) Wait() {
+	u.waiter.Wait()
+}
+
+func (u *Unbounded[E]) run(ctx context.Context) {
+	var q queue[E]
+	highWater := u.options.highWater
+	done := ctx.Done()
+	for {
+		var out chan<- E
+		var next E
+		if q.len > 0 {
+			out = u.out
+			next = q.peek()
+		}
+		select {
+		case e, ok := <-u.in:
+			if !ok {
+				u.drain(ctx, &q)
+				return
+			}
+			q.push(e)
+			u.len.Store(int64(q.len))
+			if highWater > 0 && q.len >= highWater {
+				u.options.onHighWater(q.len)
+				highWater *= 2
+			}
+		case out <- next:
+			q.pop()
+			u.len.Store(int64(q.len))
+			if q.len < u.options.highWater {
+				highWater = u.options.highWater // Report the next burst.
+			}
+		case <-done:
+			close(u.out)
+			q.clear()
+			u.len.Store(0)
+			for range u.in { // Discard the values until the input is closed.
+			}
+			return
+		}
+	}
+}
+
+// drain delivers the remaining values, then closes the output channel.
+func (u *Unbounded[E]) drain(ctx context.Context, q *queue[E]) {
+	defer close(u.out)
+	done := ctx.Done()
+	for q.len > 0 {
+		select {
+		case u.out <- q.peek():
+			q.pop()
+			u.len.Store(int64(q.len))
+		case <-done:
+			q.clear()
+			u.len.Store(0)
+			return
+		}
+	}
+}
+
+type unboundedOptions struct {
+	highWater   int
+	onHighWater func(n int)
+}
+
+func buildUnboundedOptions(opts ...UnboundedOption) *unboundedOptions {
+	o := &unboundedOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnboundedOption is an option for [NewUnbounded].
+type UnboundedOption func(*unboundedOptions)
+
+// WithHighWaterMark sets a function that is called when the queue length reaches n.
+//
+// The threshold is doubled after each call, so f is called again only if the queue keeps growing.
+// It is reset to n when the queue length drops below n.
+// It receives the current queue length.
+// It is called from the [Unbounded] goroutine, so it must not block.
+// If n <= 0, it is disabled.
+func WithHighWaterMark(n int, f func(n int)) UnboundedOption {
+	return func(o *unboundedOptions) {
+		o.highWater = n
+		o.onHighWater = f
+	}
+}
+
+const queueMinCap = 16
+
+// queue is a FIFO queue backed by a growable ring buffer.
+type queue[E any] struct {
+	buf  []E
+	head int
+	len  int
+}
+
+func (q *queue[E]) push(e E) {
+	if q.len == len(q.buf) {
+		q.resize(max(len(q.buf)*2, queueMinCap))
+	}
+	q.buf[(q.head+q.len)%len(q.buf)] = e
+	q.len++
+}
+
+func (q *queue[E]) peek() E {
+	return q.buf[q.head]
+}
+
+func (q *queue[E]) pop() {
+	var zero E
+	q.buf[q.head] = zero // Allow GC.
+	q.head = (q.head + 1) % len(q.buf)
+	q.len--
+	if len(q.buf) > queueMinCap && q.len <= len(q.buf)/4 {
+		q.resize(len(q.buf) / 2) // Release memory after a burst.
+	}
+}
+
+func (q *queue[E]) resize(n int) {
+	buf := make([]E, n)
+	if q.head+q.len <= len(q.buf) {
+		copy(buf, q.buf[q.head:q.head+q.len])
+	} else {
+		m := copy(buf, q.buf[q.head:])
+		copy(buf[m:], q.buf[:q.len-m])
+	}
+	q.buf = buf
+	q.head = 0
+}
+
+func (q *queue[E]) clear() {
+	q.buf = nil
+	q.head = 0
+	q.len = 0
+}
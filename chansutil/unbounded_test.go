@@ -0,0 +1,181 @@
+package chansutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/synctest"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleUnbounded() {
+	ctx := context.Background()
+	u := NewUnbounded[int](ctx)
+	defer u.Wait()
+	for i := range 3 {
+		u.In() <- i
+	}
+	close(u.In())
+	for e := range u.Out() {
+		fmt.Println(e)
+	}
+	// Output:
+	// 0
+	// 1
+	// 2
+}
+
+func TestUnbounded(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		u := NewUnbounded[int](ctx)
+		defer u.Wait()
+		expected := make([]int, 1000)
+		for i := range expected {
+			expected[i] = i
+			u.In() <- i
+		}
+		synctest.Wait()
+		assert.Equal(t, u.Len(), 1000)
+		close(u.In())
+		var res []int
+		for e := range u.Out() {
+			res = append(res, e)
+		}
+		assert.SliceEqual(t, res, expected)
+		assert.Equal(t, u.Len(), 0)
+	})
+}
+
+func TestUnboundedInterleaved(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		u := NewUnbounded[int](ctx)
+		defer u.Wait()
+		var expected, res []int
+		n := 0
+		for range 10 {
+			for range 50 {
+				u.In() <- n
+				expected = append(expected, n)
+				n++
+			}
+			for range 30 {
+				res = append(res, <-u.Out())
+			}
+		}
+		close(u.In())
+		for e := range u.Out() {
+			res = append(res, e)
+		}
+		assert.SliceEqual(t, res, expected)
+	})
+}
+
+func TestUnboundedHighWaterMark(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var calls []int
+		u := NewUnbounded[int](ctx, WithHighWaterMark(10, func(n int) {
+			calls = append(calls, n)
+		}))
+		defer u.Wait()
+		for i := range 50 {
+			u.In() <- i
+		}
+		close(u.In())
+		for range u.Out() {
+		}
+		assert.SliceEqual(t, calls, []int{10, 20, 40})
+	})
+}
+
+func TestUnboundedHighWaterMarkReset(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		var calls []int
+		u := NewUnbounded[int](ctx, WithHighWaterMark(10, func(n int) {
+			calls = append(calls, n)
+		}))
+		defer u.Wait()
+		for range 2 {
+			for i := range 25 {
+				u.In() <- i
+			}
+			for range 25 {
+				<-u.Out()
+			}
+		}
+		close(u.In())
+		synctest.Wait()
+		assert.SliceEqual(t, calls, []int{10, 20, 10, 20})
+	})
+}
+
+func TestUnboundedContextCancelled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		u := NewUnbounded[int](ctx)
+		defer u.Wait()
+		for i := range 10 {
+			u.In() <- i
+		}
+		cancel()
+		synctest.Wait()
+		_, ok := <-u.Out()
+		assert.False(t, ok)
+		assert.Equal(t, u.Len(), 0)
+		u.In() <- 10 // Doesn't block.
+		close(u.In())
+	})
+}
+
+func TestUnboundedContextCancelledDrain(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		u := NewUnbounded[int](ctx)
+		defer u.Wait()
+		for i := range 10 {
+			u.In() <- i
+		}
+		close(u.In())
+		assert.Equal(t, <-u.Out(), 0)
+		cancel()
+		synctest.Wait()
+		_, ok := <-u.Out()
+		assert.False(t, ok)
+		assert.Equal(t, u.Len(), 0)
+	})
+}
+
+func TestQueue(t *testing.T) {
+	var q queue[int]
+	n := 0
+	for range 5 {
+		for range 100 {
+			q.push(n)
+			n++
+		}
+		for range 70 {
+			q.pop()
+		}
+	}
+	assert.Equal(t, q.len, 150)
+	for i := range 150 {
+		assert.Equal(t, q.peek(), 350+i)
+		q.pop()
+	}
+	assert.Equal(t, q.len, 0)
+	assert.LessOrEqual(t, len(q.buf), queueMinCap*2)
+}
+
+func BenchmarkUnbounded(b *testing.B) {
+	u := NewUnbounded[int](b.Context())
+	defer u.Wait()
+	defer close(u.In())
+	for b.Loop() {
+		u.In() <- 1
+		<-u.Out()
+	}
+}
@@ -0,0 +1,188 @@
+package errorhandle
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pierrre/go-libs/chansutil"
+	"github.com/pierrre/go-libs/goroutine"
+)
+
+// AsyncHandler is a [Handler] that handles errors asynchronously.
+//
+// The errors are stored in a bounded queue, and passed to the wrapped [Handler] by workers.
+// The [context.Context] passed to the wrapped [Handler] is not canceled.
+//
+// It must be created with [NewAsyncHandler], and closed with [AsyncHandler.Close].
+type AsyncHandler struct {
+	handler Handler
+	policy  chansutil.Policy
+	queue   chan asyncItem
+	dropped atomic.Uint64
+
+	mu        sync.RWMutex
+	closed    bool
+	done      chan struct{}
+	closeOnce sync.Once
+	running   atomic.Int64
+	stopped   chan struct{}
+	waiter    goroutine.Waiter
+}
+
+type asyncItem struct {
+	ctx context.Context //nolint:containedctx // It is passed to the Handler by the worker.
+	err error
+}
+
+// NewAsyncHandler creates a new [AsyncHandler].
+//
+// It starts the workers.
+func NewAsyncHandler(h Handler, opts ...AsyncOption) *AsyncHandler {
+	o := buildAsyncOptions(opts...)
+	ah := &AsyncHandler{
+		handler: h,
+		policy:  o.policy,
+		queue:   make(chan asyncItem, o.queueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	workers := max(o.workers, 1)
+	ah.running.Store(int64(workers))
+	ah.waiter = goroutine.StartN(context.Background(), workers, func(_ context.Context, _ int) {
+		ah.work()
+	})
+	return ah
+}
+
+// Handle enqueues the error.
+//
+// If the queue is full, the behavior depends on the [chansutil.Policy].
+// With [chansutil.PolicyBlock], it waits until there is space in the queue, or the handler is closed (then it is handled synchronously).
+// [chansutil.PolicyDisconnect] is not supported, and behaves like [chansutil.PolicyDropNewest].
+//
+// After [AsyncHandler.Close] is called, the error is handled synchronously.
+func (ah *AsyncHandler) Handle(ctx context.Context, err error) {
+	if !ah.enqueue(asyncItem{ctx: ctx, err: err}) {
+		ah.handler(ctx, err)
+	}
+}
+
+// enqueue enqueues an item, and returns false if the handler is closed.
+func (ah *AsyncHandler) enqueue(it asyncItem) bool {
+	ah.mu.RLock()
+	defer ah.mu.RUnlock()
+	if ah.closed {
+		return false
+	}
+	select {
+	case ah.queue <- it:
+		return true
+	default:
+	}
+	switch ah.policy { //nolint:exhaustive // PolicyDisconnect behaves like PolicyDropNewest.
+	case chansutil.PolicyBlock:
+		select {
+		case ah.queue <- it:
+			return true
+		case <-ah.done:
+			return false
+		}
+	case chansutil.PolicyDropOldest:
+		select {
+		case <-ah.queue:
+			ah.dropped.Add(1)
+		default:
+		}
+		select {
+		case ah.queue <- it:
+			return true
+		default:
+		}
+	}
+	ah.dropped.Add(1)
+	return true
+}
+
+func (ah *AsyncHandler) work() {
+	defer func() {
+		if ah.running.Add(-1) == 0 {
+			close(ah.stopped) // Notify Close that the queue is drained.
+		}
+	}()
+	for it := range ah.queue {
+		ah.handler(context.WithoutCancel(it.ctx), it.err)
+	}
+}
+
+// Dropped returns the number of dropped errors.
+func (ah *AsyncHandler) Dropped() uint64 {
+	return ah.dropped.Load()
+}
+
+// Close stops accepting errors in the queue, and waits until the queue is drained.
+//
+// If the [context.Context] is done before, it returns the context error.
+// The workers continue to drain the queue in the background.
+//
+// It is safe to call it multiple times.
+func (ah *AsyncHandler) Close(ctx context.Context) error {
+	ah.closeOnce.Do(func() {
+		close(ah.done) // Unblock the pending Handle calls, before acquiring the lock.
+		ah.mu.Lock()
+		ah.closed = true
+		ah.mu.Unlock()
+		close(ah.queue) // Stop the workers once the queue is drained.
+	})
+	select {
+	case <-ah.stopped:
+		ah.waiter.Wait() // The workers are stopped, it propagates their panics.
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx) //nolint:wrapcheck // We want to return the original context error.
+	}
+}
+
+type asyncOptions struct {
+	queueSize int
+	workers   int
+	policy    chansutil.Policy
+}
+
+func buildAsyncOptions(opts ...AsyncOption) *asyncOptions {
+	o := &asyncOptions{
+		queueSize: 1024,
+		workers:   1,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// AsyncOption is an option for [NewAsyncHandler].
+type AsyncOption func(*asyncOptions)
+
+// WithAsyncQueueSize sets the size of the queue.
+// The default value is 1024.
+func WithAsyncQueueSize(n int) AsyncOption {
+	return func(o *asyncOptions) {
+		o.queueSize = n
+	}
+}
+
+// WithAsyncWorkers sets the number of workers.
+// The default value is 1.
+func WithAsyncWorkers(n int) AsyncOption {
+	return func(o *asyncOptions) {
+		o.workers = n
+	}
+}
+
+// WithAsyncPolicy sets the [chansutil.Policy] used when the queue is full.
+// The default value is [chansutil.PolicyBlock].
+func WithAsyncPolicy(p chansutil.Policy) AsyncOption {
+	return func(o *asyncOptions) {
+		o.policy = p
+	}
+}
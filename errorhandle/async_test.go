@@ -0,0 +1,166 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/chansutil"
+)
+
+func ExampleAsyncHandler() {
+	ctx := context.Background()
+	ah := NewAsyncHandler(func(ctx context.Context, err error) {
+		fmt.Println("Error:", err)
+	})
+	ah.Handle(ctx, errors.New("test"))
+	err := ah.Close(ctx)
+	if err != nil {
+		panic(err)
+	}
+	// Output: Error: test
+}
+
+func TestAsyncHandler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		ah := NewAsyncHandler(r.handle, WithAsyncWorkers(4))
+		expected := make([]string, 100)
+		for i := range expected {
+			expected[i] = fmt.Sprint(i)
+			ah.Handle(ctx, errors.New(expected[i]))
+		}
+		err := ah.Close(ctx)
+		assert.NoError(t, err)
+		assert.SliceLen(t, r.take(), 100)
+		assert.Equal(t, ah.Dropped(), 0)
+		ah.Handle(ctx, errors.New("closed"))
+		assert.SliceEqual(t, r.take(), []string{"closed"})
+		err = ah.Close(ctx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestAsyncHandlerContextNotCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		var handlerCtxErr error
+		ah := NewAsyncHandler(func(ctx context.Context, err error) {
+			handlerCtxErr = ctx.Err()
+		})
+		ah.Handle(ctx, errors.New("test"))
+		cancel()
+		err := ah.Close(t.Context())
+		assert.NoError(t, err)
+		assert.NoError(t, handlerCtxErr)
+	})
+}
+
+func newTestAsyncHandlerBlocked(policy chansutil.Policy, r *testRecorder) (*AsyncHandler, chan struct{}) {
+	unblock := make(chan struct{})
+	ah := NewAsyncHandler(func(ctx context.Context, err error) {
+		<-unblock
+		r.handle(ctx, err)
+	}, WithAsyncQueueSize(2), WithAsyncPolicy(policy))
+	return ah, unblock
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		ah, unblock := newTestAsyncHandlerBlocked(chansutil.PolicyBlock, r)
+		for i := range 3 {
+			ah.Handle(ctx, fmt.Errorf("%d", i))
+		}
+		synctest.Wait() // The worker is blocked with "0", the queue contains "1" and "2".
+		handled := false
+		go func() {
+			ah.Handle(ctx, errors.New("3"))
+			handled = true
+		}()
+		synctest.Wait()
+		assert.False(t, handled)
+		close(unblock)
+		synctest.Wait()
+		assert.True(t, handled)
+		err := ah.Close(ctx)
+		assert.NoError(t, err)
+		assert.SliceEqual(t, r.take(), []string{"0", "1", "2", "3"})
+		assert.Equal(t, ah.Dropped(), 0)
+	})
+}
+
+func TestAsyncHandlerBlockClose(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		ah, unblock := newTestAsyncHandlerBlocked(chansutil.PolicyBlock, r)
+		for i := range 3 {
+			ah.Handle(ctx, fmt.Errorf("%d", i))
+		}
+		synctest.Wait()
+		go ah.Handle(ctx, errors.New("3")) // Handled synchronously after Close.
+		synctest.Wait()
+		closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		err := ah.Close(closeCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(unblock)
+		err = ah.Close(ctx)
+		assert.NoError(t, err)
+		synctest.Wait()
+		assert.SliceLen(t, r.take(), 4)
+	})
+}
+
+func TestAsyncHandlerDropNewest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		ah, unblock := newTestAsyncHandlerBlocked(chansutil.PolicyDropNewest, r)
+		for i := range 5 {
+			ah.Handle(ctx, fmt.Errorf("%d", i))
+			synctest.Wait()
+		}
+		close(unblock)
+		err := ah.Close(ctx)
+		assert.NoError(t, err)
+		assert.SliceEqual(t, r.take(), []string{"0", "1", "2"})
+		assert.Equal(t, ah.Dropped(), 2)
+	})
+}
+
+func TestAsyncHandlerDropOldest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		ah, unblock := newTestAsyncHandlerBlocked(chansutil.PolicyDropOldest, r)
+		for i := range 5 {
+			ah.Handle(ctx, fmt.Errorf("%d", i))
+			synctest.Wait()
+		}
+		close(unblock)
+		err := ah.Close(ctx)
+		assert.NoError(t, err)
+		assert.SliceEqual(t, r.take(), []string{"0", "3", "4"})
+		assert.Equal(t, ah.Dropped(), 2)
+	})
+}
+
+func BenchmarkAsyncHandler(b *testing.B) {
+	ctx := b.Context()
+	ah := NewAsyncHandler(func(ctx context.Context, err error) {}, WithAsyncPolicy(chansutil.PolicyDropNewest))
+	defer func() {
+		_ = ah.Close(ctx)
+	}()
+	err := errors.New("error")
+	for b.Loop() {
+		ah.Handle(ctx, err)
+	}
+}
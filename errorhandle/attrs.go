@@ -0,0 +1,142 @@
+package errorhandle
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+type attrsContextKey struct{}
+
+// attrsNode is a node of a linked list of attributes.
+// Each [context.Context] references the last node, so adding attributes doesn't copy the previous ones.
+type attrsNode struct {
+	parent *attrsNode
+	attrs  []slog.Attr
+	len    int
+}
+
+// WithAttrs adds attributes to a [context.Context].
+//
+// The arguments are converted to attributes like [slog.Logger.Log]: a [slog.Attr], or a string key followed by a value.
+// They are added after the attributes already present in the [context.Context].
+// The previous attributes are not copied.
+//
+// They can be retrieved with [GetAttrsFromContext].
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	if len(args) == 0 {
+		return ctx
+	}
+	return addAttrsToContext(ctx, slog.Group("", args...).Value.Group())
+}
+
+// addAttrsToContext adds attributes to a [context.Context].
+// The slice must not be modified after.
+func addAttrsToContext(ctx context.Context, attrs []slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	parent := getAttrsNode(ctx)
+	n := &attrsNode{
+		parent: parent,
+		attrs:  attrs,
+		len:    len(attrs),
+	}
+	if parent != nil {
+		n.len += parent.len
+	}
+	return context.WithValue(ctx, attrsContextKey{}, n)
+}
+
+// GetAttrsFromContext returns the attributes added to a [context.Context].
+//
+// It returns a new slice.
+func GetAttrsFromContext(ctx context.Context) []slog.Attr {
+	return appendAttrsFromContext(ctx, nil)
+}
+
+func appendAttrsFromContext(ctx context.Context, dst []slog.Attr) []slog.Attr {
+	n := getAttrsNode(ctx)
+	if n == nil {
+		return dst
+	}
+	start := len(dst)
+	dst = slices.Grow(dst, n.len)[:start+n.len]
+	i := len(dst)
+	for ; n != nil; n = n.parent {
+		i -= len(n.attrs)
+		copy(dst[i:], n.attrs)
+	}
+	return dst
+}
+
+func getAttrsNode(ctx context.Context) *attrsNode {
+	n, _ := ctx.Value(attrsContextKey{}).(*attrsNode)
+	return n
+}
+
+// appendText appends the attributes as " key=value" to a []byte.
+//
+// The format is similar to [slog.TextHandler]: the values are quoted if needed, and the groups are flattened as "group.key=value".
+func (n *attrsNode) appendText(dst []byte) []byte {
+	if n.parent != nil {
+		dst = n.parent.appendText(dst)
+	}
+	for _, a := range n.attrs {
+		dst = appendAttrText(dst, "", a)
+	}
+	return dst
+}
+
+func appendAttrText(dst []byte, prefix string, a slog.Attr) []byte { //nolint:gocritic // slog.Attr is passed by value in the slog package.
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			dst = appendAttrText(dst, prefix, ga)
+		}
+		return dst
+	}
+	if a.Key == "" && v.Any() == nil {
+		return dst // Empty attribute.
+	}
+	dst = append(dst, ' ')
+	dst = appendTextString(dst, prefix+a.Key)
+	dst = append(dst, '=')
+	switch v.Kind() { //nolint:exhaustive // The other kinds use the default format.
+	case slog.KindInt64:
+		dst = strconv.AppendInt(dst, v.Int64(), 10)
+	case slog.KindUint64:
+		dst = strconv.AppendUint(dst, v.Uint64(), 10)
+	case slog.KindBool:
+		dst = strconv.AppendBool(dst, v.Bool())
+	default:
+		dst = appendTextString(dst, v.String())
+	}
+	return dst
+}
+
+// appendTextString appends a string, quoted if it is empty or contains spaces, '=', '"', or non-printable characters.
+func appendTextString(dst []byte, s string) []byte {
+	if textNeedsQuoting(s) {
+		return strconv.AppendQuote(dst, s)
+	}
+	return append(dst, s...)
+}
+
+func textNeedsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
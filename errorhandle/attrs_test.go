@@ -0,0 +1,65 @@
+package errorhandle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleWithAttrs() {
+	ctx := context.Background()
+	ctx = WithAttrs(ctx, "tenant", "acme")
+	ctx = WithAttrs(ctx, "job", "sync", slog.Int("attempt", 2))
+	for _, a := range GetAttrsFromContext(ctx) {
+		fmt.Println(a)
+	}
+	// Output:
+	// tenant=acme
+	// job=sync
+	// attempt=2
+}
+
+func TestWithAttrs(t *testing.T) {
+	ctx := t.Context()
+	assert.Equal(t, WithAttrs(ctx), ctx)
+	ctx = WithAttrs(ctx, "a", 1, slog.String("b", "2"))
+	ctx = WithAttrs(ctx, "c", true)
+	assert.DeepEqual(t, GetAttrsFromContext(ctx), []slog.Attr{slog.Int("a", 1), slog.String("b", "2"), slog.Bool("c", true)})
+}
+
+func BenchmarkWithAttrs(b *testing.B) {
+	ctx := b.Context()
+	ctx = WithAttrs(ctx, "a", 1)
+	for b.Loop() {
+		_ = WithAttrs(ctx, "b", 2)
+	}
+}
+
+func TestGetAttrsFromContext(t *testing.T) {
+	ctx := t.Context()
+	assert.SliceEmpty(t, GetAttrsFromContext(ctx))
+	ctx1 := WithAttrs(ctx, "a", "1", "b", "2")
+	ctx2 := WithAttrs(ctx1, "c", "3")
+	ctx3 := WithAttrs(ctx1, "d", "4")
+	assert.DeepEqual(t, GetAttrsFromContext(ctx2), []slog.Attr{slog.String("a", "1"), slog.String("b", "2"), slog.String("c", "3")})
+	assert.DeepEqual(t, GetAttrsFromContext(ctx3), []slog.Attr{slog.String("a", "1"), slog.String("b", "2"), slog.String("d", "4")})
+}
+
+func TestAttrsAppendText(t *testing.T) {
+	ctx := t.Context()
+	ctx = WithAttrs(ctx,
+		"a", "b c",
+		"d", "e=f",
+		"g", "",
+		"h", "i\nj",
+		"k l", 1,
+		slog.Group("m", "n", "o", slog.Group("p", "q", true)),
+		slog.Group("", "r", "s"),
+		slog.Attr{},
+	)
+	res := getAttrsNode(ctx).appendText(nil)
+	assert.Equal(t, string(res), ` a="b c" d="e=f" g="" h="i\nj" "k l"=1 m.n=o m.p.q=true r=s`)
+}
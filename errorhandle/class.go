@@ -0,0 +1,170 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+)
+
+// Class is the class of an error.
+type Class string
+
+// Built-in classes.
+const (
+	// ClassUnknown is returned by [Classify] if the error is not classified.
+	ClassUnknown Class = ""
+	// ClassCanceled is the class of canceled operations, e.g. [context.Canceled].
+	ClassCanceled Class = "canceled"
+	// ClassTimeout is the class of timeouts, e.g. [context.DeadlineExceeded].
+	ClassTimeout Class = "timeout"
+	// ClassNotFound is the class of missing resources, e.g. [os.ErrNotExist].
+	ClassNotFound Class = "not_found"
+)
+
+// ClassError is an error with a [Class].
+type ClassError struct {
+	Err   error
+	Class Class
+}
+
+// WithClass returns an error with a [Class].
+//
+// It returns nil if err is nil.
+func WithClass(err error, c Class) error {
+	if err == nil {
+		return nil
+	}
+	return &ClassError{
+		Err:   err,
+		Class: c,
+	}
+}
+
+func (err *ClassError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ClassError) Unwrap() error {
+	return err.Err
+}
+
+// GetClass returns the [Class] set with [WithClass].
+//
+// It returns false if there is no [Class].
+func GetClass(err error) (Class, bool) {
+	classErr, ok := errors.AsType[*ClassError](err)
+	if !ok {
+		return ClassUnknown, false
+	}
+	return classErr.Class, true
+}
+
+// Classifier returns the [Class] of an error.
+//
+// It returns false if it doesn't know the error.
+type Classifier func(err error) (Class, bool)
+
+// DefaultClassifiers is the default list of [Classifier] used by [Classify] and [NewRouter].
+var DefaultClassifiers = []Classifier{
+	ClassifyContext,
+	ClassifyNetTimeout,
+	ClassifyNotExist,
+}
+
+// ClassifyContext is a [Classifier] for [context.Canceled] ([ClassCanceled]) and [context.DeadlineExceeded] ([ClassTimeout]).
+func ClassifyContext(err error) (Class, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassTimeout, true
+	}
+	return ClassUnknown, false
+}
+
+// ClassifyNetTimeout is a [Classifier] for [net.Error] timeouts ([ClassTimeout]).
+func ClassifyNetTimeout(err error) (Class, bool) {
+	netErr, ok := errors.AsType[net.Error](err)
+	if ok && netErr.Timeout() {
+		return ClassTimeout, true
+	}
+	return ClassUnknown, false
+}
+
+// ClassifyNotExist is a [Classifier] for [os.ErrNotExist] ([ClassNotFound]).
+func ClassifyNotExist(err error) (Class, bool) {
+	if errors.Is(err, os.ErrNotExist) {
+		return ClassNotFound, true
+	}
+	return ClassUnknown, false
+}
+
+// Classify returns the [Class] of an error.
+//
+// It first checks the [Class] set with [WithClass], then calls the classifiers in order.
+// If classifiers is empty, [DefaultClassifiers] is used.
+// It returns [ClassUnknown] if the error is not classified.
+func Classify(err error, classifiers ...Classifier) Class {
+	if c, ok := GetClass(err); ok {
+		return c
+	}
+	if len(classifiers) == 0 {
+		classifiers = DefaultClassifiers
+	}
+	for _, cl := range classifiers {
+		if c, ok := cl(err); ok {
+			return c
+		}
+	}
+	return ClassUnknown
+}
+
+// ClassFilter returns a filter that returns true if the error has one of the classes.
+//
+// It can be used with [FilterHandler].
+// See [Classify] for the classifiers.
+func ClassFilter(classes []Class, classifiers ...Classifier) func(ctx context.Context, err error) bool {
+	return func(ctx context.Context, err error) bool {
+		return slices.Contains(classes, Classify(err, classifiers...))
+	}
+}
+
+// Route is a route of [NewRouter].
+type Route struct {
+	Class Class
+	// Handler handles the errors of the Class.
+	// If it is nil, the errors are discarded.
+	Handler Handler
+}
+
+// NewRouter returns a [Handler] that dispatches errors by [Class].
+//
+// Each route is a [FilterHandler], and they are called in order as [Handlers].
+// The errors that don't match any route are passed to fallback, if it is not nil.
+// See [Classify] for the classifiers.
+func NewRouter(routes []Route, fallback Handler, classifiers ...Classifier) Handler {
+	hs := make(Handlers, 0, len(routes)+1)
+	classes := make([]Class, 0, len(routes))
+	for _, r := range routes {
+		classes = append(classes, r.Class)
+		if r.Handler == nil {
+			continue
+		}
+		hs = append(hs, FilterHandler{
+			Handler: r.Handler,
+			Filter:  ClassFilter([]Class{r.Class}, classifiers...),
+		}.Handle)
+	}
+	if fallback != nil {
+		filter := ClassFilter(classes, classifiers...)
+		hs = append(hs, FilterHandler{
+			Handler: fallback,
+			Filter: func(ctx context.Context, err error) bool {
+				return !filter(ctx, err)
+			},
+		}.Handle)
+	}
+	return hs.Handle
+}
@@ -0,0 +1,133 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleNewRouter() {
+	ctx := context.Background()
+	h := NewRouter(
+		[]Route{
+			{Class: ClassCanceled}, // Discarded.
+			{Class: ClassNotFound, Handler: func(ctx context.Context, err error) {
+				fmt.Println("Not found:", err)
+			}},
+		},
+		func(ctx context.Context, err error) {
+			fmt.Println("Error:", err)
+		},
+	)
+	h(ctx, context.Canceled)
+	h(ctx, fmt.Errorf("open: %w", os.ErrNotExist))
+	h(ctx, errors.New("test"))
+	// Output:
+	// Not found: open: file does not exist
+	// Error: test
+}
+
+func TestWithClass(t *testing.T) {
+	errTest := errors.New("test")
+	err := WithClass(errTest, "custom")
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, err.Error(), "test")
+	c, ok := GetClass(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, c, "custom")
+	assert.NoError(t, WithClass(nil, "custom"))
+}
+
+func TestGetClassNotSet(t *testing.T) {
+	c, ok := GetClass(errors.New("test"))
+	assert.False(t, ok)
+	assert.Equal(t, c, ClassUnknown)
+}
+
+type testNetError struct {
+	timeout bool
+}
+
+func (err *testNetError) Error() string   { return "net error" }
+func (err *testNetError) Timeout() bool   { return err.timeout }
+func (err *testNetError) Temporary() bool { return false }
+
+var _ net.Error = &testNetError{}
+
+func TestClassify(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected Class
+	}{
+		{"Unknown", errors.New("test"), ClassUnknown},
+		{"Canceled", fmt.Errorf("wrapped: %w", context.Canceled), ClassCanceled},
+		{"DeadlineExceeded", context.DeadlineExceeded, ClassTimeout},
+		{"NetTimeout", &testNetError{timeout: true}, ClassTimeout},
+		{"NetNotTimeout", &testNetError{}, ClassUnknown},
+		{"NotExist", &os.PathError{Op: "open", Path: "test", Err: os.ErrNotExist}, ClassNotFound},
+		{"WithClass", WithClass(context.Canceled, "custom"), "custom"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, Classify(tc.err), tc.expected)
+		})
+	}
+}
+
+func TestClassifyCustom(t *testing.T) {
+	cl := func(err error) (Class, bool) {
+		return "custom", true
+	}
+	assert.Equal(t, Classify(context.Canceled, cl), "custom")
+}
+
+func TestNewRouter(t *testing.T) {
+	ctx := t.Context()
+	var calls []string
+	newHandler := func(name string) Handler {
+		return func(ctx context.Context, err error) {
+			calls = append(calls, name)
+		}
+	}
+	h := NewRouter(
+		[]Route{
+			{Class: ClassCanceled},
+			{Class: ClassTimeout, Handler: newHandler("timeout1")},
+			{Class: ClassTimeout, Handler: newHandler("timeout2")},
+		},
+		newHandler("fallback"),
+	)
+	h(ctx, context.Canceled)
+	assert.SliceEmpty(t, calls)
+	h(ctx, context.DeadlineExceeded)
+	assert.SliceEqual(t, calls, []string{"timeout1", "timeout2"})
+	calls = nil
+	h(ctx, os.ErrNotExist)
+	assert.SliceEqual(t, calls, []string{"fallback"})
+}
+
+func TestNewRouterNoFallback(t *testing.T) {
+	ctx := t.Context()
+	h := NewRouter(nil, nil)
+	h(ctx, errors.New("test"))
+}
+
+func BenchmarkNewRouter(b *testing.B) {
+	ctx := b.Context()
+	h := NewRouter(
+		[]Route{
+			{Class: ClassCanceled},
+			{Class: ClassTimeout, Handler: func(ctx context.Context, err error) {}},
+		},
+		func(ctx context.Context, err error) {},
+	)
+	err := errors.New("test")
+	for b.Loop() {
+		h(ctx, err)
+	}
+}
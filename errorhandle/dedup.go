@@ -0,0 +1,303 @@
+package errorhandle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DedupHandler is a [Handler] that deduplicates errors.
+//
+// The errors are grouped by key.
+// The first occurrence of a key is passed to Handler.
+// The following occurrences are counted, and a [RepeatedError] summary is passed to Handler at the end of each Window.
+// A key is forgotten after a Window without occurrence.
+//
+// The new keys that are rejected by RateLimit or MaxKeys are not reported individually.
+// They are counted in a single [SuppressedError] summary, passed to Handler at the end of the Window.
+//
+// The summaries are passed to Handler from a separate goroutine, with the [context.Context] of the last occurrence.
+// [DedupHandler.Flush] passes the pending summaries immediately, e.g. before exiting.
+//
+// It is safe for concurrent use.
+// It must not be copied after first use.
+type DedupHandler struct {
+	Handler
+
+	// Key returns the key of an error.
+	// The default value uses the error message.
+	Key func(ctx context.Context, err error) string
+
+	// Window is the duration of the deduplication window.
+	// The default value is 10 seconds.
+	Window time.Duration
+
+	// RateLimit is the maximum number of first occurrences passed to Handler per RateInterval, for all keys.
+	// The rate limited errors are counted in the [SuppressedError] summary.
+	// The default value 0 means no limit.
+	RateLimit int
+
+	// RateInterval is the interval of RateLimit.
+	// The default value is 1 second.
+	RateInterval time.Duration
+
+	// MaxKeys is the maximum number of keys tracked at the same time.
+	// The errors with a new key are counted in the [SuppressedError] summary when the limit is reached.
+	// The default value is 10000.
+	MaxKeys int
+
+	mu         sync.Mutex
+	entries    map[string]*dedupEntry
+	suppressed *dedupEntry
+	rateStart  time.Time
+	rateCount  int
+}
+
+type dedupEntry struct {
+	ctx   context.Context //nolint:containedctx // It is used to call the Handler later.
+	err   error
+	count int
+	start time.Time // Start of the current window.
+	timer *time.Timer
+}
+
+const (
+	dedupDefaultWindow       = 10 * time.Second
+	dedupDefaultRateInterval = 1 * time.Second
+	dedupDefaultMaxKeys      = 10000
+)
+
+// Handle handles the error.
+func (h *DedupHandler) Handle(ctx context.Context, err error) {
+	key := h.getKey(ctx, err)
+	if h.record(ctx, err, key) {
+		h.Handler(ctx, err)
+	}
+}
+
+// record records an occurrence, and returns true if it must be passed to Handler.
+func (h *DedupHandler) record(ctx context.Context, err error, key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.entries[key]
+	if ok {
+		e.add(ctx, err)
+		return false
+	}
+	if len(h.entries) >= h.getMaxKeys() || !h.allow() {
+		h.suppress(ctx, err)
+		return false
+	}
+	h.addEntry(key)
+	return true
+}
+
+func (h *DedupHandler) getKey(ctx context.Context, err error) string {
+	if h.Key != nil {
+		return h.Key(ctx, err)
+	}
+	return err.Error()
+}
+
+func (h *DedupHandler) getMaxKeys() int {
+	if h.MaxKeys > 0 {
+		return h.MaxKeys
+	}
+	return dedupDefaultMaxKeys
+}
+
+func (h *DedupHandler) addEntry(key string) {
+	if h.entries == nil {
+		h.entries = make(map[string]*dedupEntry)
+	}
+	e := new(dedupEntry)
+	h.entries[key] = e
+	h.scheduleFlush(key, e)
+}
+
+func (e *dedupEntry) add(ctx context.Context, err error) {
+	e.ctx = ctx
+	e.err = err
+	e.count++
+}
+
+func (e *dedupEntry) reset() {
+	e.ctx = nil
+	e.err = nil
+	e.count = 0
+}
+
+func (e *dedupEntry) repeatedError(d time.Duration) (context.Context, error) {
+	return context.WithoutCancel(e.ctx), &RepeatedError{ // The original context may be canceled.
+		Err:      e.err,
+		Count:    e.count,
+		Duration: d,
+	}
+}
+
+func (e *dedupEntry) suppressedError(d time.Duration) (context.Context, error) {
+	return context.WithoutCancel(e.ctx), &SuppressedError{ // The original context may be canceled.
+		Err:      e.err,
+		Count:    e.count,
+		Duration: d,
+	}
+}
+
+// suppress counts an error in the [SuppressedError] summary.
+func (h *DedupHandler) suppress(ctx context.Context, err error) {
+	e := h.suppressed
+	if e == nil {
+		e = new(dedupEntry)
+		h.suppressed = e
+		window := h.getWindow()
+		e.start = time.Now()
+		e.timer = time.AfterFunc(window, func() {
+			h.flushSuppressed(e, window)
+		})
+	}
+	e.add(ctx, err)
+}
+
+// flushSuppressed passes the [SuppressedError] summary to Handler.
+func (h *DedupHandler) flushSuppressed(e *dedupEntry, window time.Duration) {
+	h.mu.Lock()
+	if h.suppressed != e {
+		h.mu.Unlock()
+		return // It was passed by Flush.
+	}
+	h.suppressed = nil
+	h.mu.Unlock()
+	h.Handler(e.suppressedError(window))
+}
+
+func (h *DedupHandler) scheduleFlush(key string, e *dedupEntry) {
+	window := h.getWindow()
+	e.start = time.Now()
+	e.timer = time.AfterFunc(window, func() {
+		h.flush(key, e, window)
+	})
+}
+
+// flush passes the summary of an entry to Handler.
+// If there is no occurrence, the entry is deleted.
+func (h *DedupHandler) flush(key string, e *dedupEntry, window time.Duration) {
+	h.mu.Lock()
+	if h.entries[key] != e {
+		h.mu.Unlock()
+		return // It was removed by Flush.
+	}
+	if e.count == 0 {
+		delete(h.entries, key)
+		h.mu.Unlock()
+		return
+	}
+	ctx, err := e.repeatedError(window)
+	e.reset()
+	h.scheduleFlush(key, e) // The key is kept for another window.
+	h.mu.Unlock()
+	h.Handler(ctx, err)
+}
+
+// Flush stops the timers, and passes the pending summaries to Handler.
+//
+// The keys are forgotten, and the following errors are handled as first occurrences.
+// It can be registered with [AddShutdownHook], so the summaries are not lost when the process exits.
+// If the [context.Context] is done, it returns the context error, and the remaining summaries are dropped.
+func (h *DedupHandler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	entries := h.entries
+	suppressed := h.suppressed
+	h.entries = nil
+	h.suppressed = nil
+	h.mu.Unlock()
+	now := time.Now()
+	type summary struct {
+		ctx context.Context //nolint:containedctx // It is passed to the Handler.
+		err error
+	}
+	var summaries []summary
+	for _, e := range entries {
+		e.timer.Stop()
+		if e.count > 0 {
+			sctx, err := e.repeatedError(now.Sub(e.start))
+			summaries = append(summaries, summary{ctx: sctx, err: err})
+		}
+	}
+	if suppressed != nil {
+		suppressed.timer.Stop()
+		sctx, err := suppressed.suppressedError(now.Sub(suppressed.start))
+		summaries = append(summaries, summary{ctx: sctx, err: err})
+	}
+	for _, s := range summaries {
+		if ctx.Err() != nil {
+			return context.Cause(ctx) //nolint:wrapcheck // We want to return the original context error.
+		}
+		h.Handler(s.ctx, s.err)
+	}
+	return nil
+}
+
+func (h *DedupHandler) getWindow() time.Duration {
+	if h.Window > 0 {
+		return h.Window
+	}
+	return dedupDefaultWindow
+}
+
+// allow returns true if the rate limit allows to pass an error.
+func (h *DedupHandler) allow() bool {
+	if h.RateLimit <= 0 {
+		return true
+	}
+	interval := h.RateInterval
+	if interval <= 0 {
+		interval = dedupDefaultRateInterval
+	}
+	now := time.Now()
+	if now.Sub(h.rateStart) >= interval {
+		h.rateStart = now
+		h.rateCount = 0
+	}
+	if h.rateCount >= h.RateLimit {
+		return false
+	}
+	h.rateCount++
+	return true
+}
+
+// RepeatedError is a summary of repeated errors, created by [DedupHandler].
+type RepeatedError struct {
+	// Err is the last occurrence.
+	Err error
+	// Count is the number of occurrences.
+	Count int
+	// Duration is the duration during which the occurrences were counted.
+	Duration time.Duration
+}
+
+func (err *RepeatedError) Error() string {
+	return fmt.Sprintf("%v repeated %d times in %v", err.Err, err.Count, err.Duration)
+}
+
+func (err *RepeatedError) Unwrap() error {
+	return err.Err
+}
+
+// SuppressedError is a summary of errors with new keys that were not reported by [DedupHandler], because of the rate limit or the maximum number of keys.
+type SuppressedError struct {
+	// Err is the last suppressed error.
+	Err error
+	// Count is the number of suppressed errors.
+	Count int
+	// Duration is the duration during which the errors were counted.
+	Duration time.Duration
+}
+
+func (err *SuppressedError) Error() string {
+	return fmt.Sprintf("%d errors suppressed in %v, last: %v", err.Count, err.Duration, err.Err)
+}
+
+func (err *SuppressedError) Unwrap() error {
+	return err.Err
+}
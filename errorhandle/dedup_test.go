@@ -0,0 +1,230 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleDedupHandler() {
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	h := &DedupHandler{
+		Handler: func(ctx context.Context, err error) {
+			fmt.Println("Error:", err)
+			wg.Done()
+		},
+		Window: 100 * time.Millisecond,
+	}
+	for range 5 {
+		h.Handle(ctx, errors.New("test"))
+	}
+	wg.Wait()
+	// Output:
+	// Error: test
+	// Error: test repeated 4 times in 100ms
+}
+
+func TestDedupHandler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler: r.handle,
+		}
+		for range 100 {
+			h.Handle(ctx, errors.New("a"))
+			h.Handle(ctx, errors.New("b"))
+		}
+		assert.SliceEqual(t, r.take(), []string{"a", "b"})
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		errs := r.take()
+		assert.SliceLen(t, errs, 2)
+		assert.SliceContains(t, errs, "a repeated 99 times in 10s")
+		assert.SliceContains(t, errs, "b repeated 99 times in 10s")
+		h.Handle(ctx, errors.New("a"))
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		assert.SliceEqual(t, r.take(), []string{"a repeated 1 times in 10s"})
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		assert.SliceEmpty(t, r.take())
+		h.mu.Lock()
+		assert.MapEmpty(t, h.entries)
+		h.mu.Unlock()
+		h.Handle(ctx, errors.New("a"))
+		assert.SliceEqual(t, r.take(), []string{"a"})
+	})
+}
+
+func TestDedupHandlerKey(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler: r.handle,
+			Key: func(ctx context.Context, err error) string {
+				return "key"
+			},
+			Window: time.Second,
+		}
+		h.Handle(ctx, errors.New("a"))
+		h.Handle(ctx, errors.New("b"))
+		h.Handle(ctx, errors.New("c"))
+		time.Sleep(time.Second)
+		synctest.Wait()
+		assert.SliceEqual(t, r.take(), []string{"a", "c repeated 2 times in 1s"})
+	})
+}
+
+func TestDedupHandlerRepeatedError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		errTest := errors.New("test")
+		var errs []error
+		h := &DedupHandler{
+			Handler: func(ctx context.Context, err error) {
+				errs = append(errs, err)
+			},
+		}
+		h.Handle(ctx, errTest)
+		h.Handle(ctx, errTest)
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		assert.SliceLen(t, errs, 2)
+		var repeatedErr *RepeatedError
+		assert.ErrorAs(t, errs[1], &repeatedErr)
+		assert.Equal(t, repeatedErr.Count, 1)
+		assert.ErrorIs(t, errs[1], errTest)
+	})
+}
+
+func TestDedupHandlerRateLimit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler:   r.handle,
+			RateLimit: 2,
+		}
+		for i := range 5 {
+			h.Handle(ctx, fmt.Errorf("error %d", i))
+		}
+		assert.SliceEqual(t, r.take(), []string{"error 0", "error 1"})
+		time.Sleep(time.Second)
+		h.Handle(ctx, errors.New("error 5"))
+		assert.SliceEqual(t, r.take(), []string{"error 5"})
+		time.Sleep(9 * time.Second)
+		synctest.Wait()
+		assert.SliceEqual(t, r.take(), []string{"3 errors suppressed in 10s, last: error 4"})
+		h.mu.Lock()
+		assert.MapLen(t, h.entries, 1) // Only "error 5" is kept for another window.
+		h.mu.Unlock()
+	})
+}
+
+func TestDedupHandlerMaxKeys(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler: r.handle,
+			MaxKeys: 2,
+		}
+		for i := range 5 {
+			h.Handle(ctx, fmt.Errorf("error %d", i))
+		}
+		h.Handle(ctx, errors.New("error 0"))
+		assert.SliceEqual(t, r.take(), []string{"error 0", "error 1"})
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		errs := r.take()
+		assert.SliceLen(t, errs, 2)
+		assert.SliceContains(t, errs, "error 0 repeated 1 times in 10s")
+		assert.SliceContains(t, errs, "3 errors suppressed in 10s, last: error 4")
+	})
+}
+
+func TestDedupHandlerSuppressedError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		errTest := errors.New("test")
+		var errs []error
+		h := &DedupHandler{
+			Handler: func(ctx context.Context, err error) {
+				errs = append(errs, err)
+			},
+			RateLimit: 1,
+		}
+		h.Handle(ctx, errors.New("first"))
+		h.Handle(ctx, errTest)
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		assert.SliceLen(t, errs, 2)
+		var suppressedErr *SuppressedError
+		assert.ErrorAs(t, errs[1], &suppressedErr)
+		assert.Equal(t, suppressedErr.Count, 1)
+		assert.ErrorIs(t, errs[1], errTest)
+	})
+}
+
+func TestDedupHandlerFlush(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler:   r.handle,
+			RateLimit: 1,
+		}
+		for range 3 {
+			h.Handle(ctx, errors.New("a"))
+		}
+		h.Handle(ctx, errors.New("b"))
+		assert.SliceEqual(t, r.take(), []string{"a"})
+		time.Sleep(5 * time.Second)
+		err := h.Flush(ctx)
+		assert.NoError(t, err)
+		assert.SliceEqual(t, r.take(), []string{"a repeated 2 times in 5s", "1 errors suppressed in 5s, last: b"})
+		time.Sleep(10 * time.Second)
+		synctest.Wait()
+		assert.SliceEmpty(t, r.take()) // The timers are stopped.
+		time.Sleep(time.Second)
+		h.Handle(ctx, errors.New("a"))
+		assert.SliceEqual(t, r.take(), []string{"a"})
+	})
+}
+
+func TestDedupHandlerFlushContextCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		r := new(testRecorder)
+		h := &DedupHandler{
+			Handler: r.handle,
+		}
+		h.Handle(ctx, errors.New("a"))
+		h.Handle(ctx, errors.New("a"))
+		cancel()
+		err := h.Flush(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.SliceEqual(t, r.take(), []string{"a"})
+	})
+}
+
+func BenchmarkDedupHandler(b *testing.B) {
+	ctx := b.Context()
+	h := &DedupHandler{
+		Handler: func(ctx context.Context, err error) {},
+	}
+	err := errors.New("error")
+	for b.Loop() {
+		h.Handle(ctx, err)
+	}
+}
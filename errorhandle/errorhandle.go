@@ -4,8 +4,10 @@ package errorhandle
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/pierrre/go-libs/bytesutil"
 	"github.com/pierrre/go-libs/syncutil/atomicutil"
 )
 
@@ -78,6 +80,25 @@ func (f FilterHandler) Handle(ctx context.Context, err error) {
 }
 
 // StderrHandler is a [Handler] that writes the error to [os.Stderr].
+//
+// The attributes from the [context.Context] (see [WithAttrs]) are written after the error message, as "key=value".
 func StderrHandler(ctx context.Context, err error) {
-	_, _ = fmt.Fprintln(os.Stderr, err)
+	_ = writeError(ctx, os.Stderr, err)
 }
+
+func writeError(ctx context.Context, w io.Writer, err error) error {
+	n := getAttrsNode(ctx)
+	if n == nil {
+		_, writeErr := fmt.Fprintln(w, err)
+		return writeErr //nolint:wrapcheck // Not needed.
+	}
+	bw := bytesWriterPool.Get()
+	defer bytesWriterPool.Put(bw)
+	bw.AppendString(err.Error())
+	*bw = n.appendText(*bw)
+	bw.AppendByte('\n')
+	_, writeErr := w.Write(*bw)
+	return writeErr //nolint:wrapcheck // Not needed.
+}
+
+var bytesWriterPool = &bytesutil.WriterPool{}
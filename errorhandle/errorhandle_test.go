@@ -1,9 +1,13 @@
 package errorhandle
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"sync"
 	"testing"
 
 	"github.com/pierrre/assert"
@@ -18,6 +22,27 @@ func Example() {
 	// Output: Error: test
 }
 
+// testRecorder records the messages of the handled errors.
+type testRecorder struct {
+	mu   sync.Mutex
+	errs []string
+}
+
+func (r *testRecorder) handle(ctx context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err.Error())
+}
+
+// take returns the recorded messages, and resets them.
+func (r *testRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	errs := r.errs
+	r.errs = nil
+	return errs
+}
+
 func TestSetHandlerToContext(t *testing.T) {
 	ctx := t.Context()
 	h := func(ctx context.Context, err error) {}
@@ -101,3 +126,36 @@ func TestFilterHandlerFalse(t *testing.T) {
 	}
 	fh.Handle(ctx, errors.New("error"))
 }
+
+func TestStderrHandler(t *testing.T) {
+	ctx := t.Context()
+	StderrHandler(ctx, errors.New("error"))
+}
+
+func TestWriteError(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	err := writeError(ctx, buf, errors.New("error"))
+	assert.NoError(t, err)
+	assert.Equal(t, buf.String(), "error\n")
+}
+
+func TestWriteErrorAttrs(t *testing.T) {
+	ctx := t.Context()
+	ctx = WithAttrs(ctx, "request_id", "123")
+	ctx = WithAttrs(ctx, slog.Int("attempt", 2), "job", "sync")
+	buf := new(bytes.Buffer)
+	err := writeError(ctx, buf, errors.New("error"))
+	assert.NoError(t, err)
+	assert.Equal(t, buf.String(), "error request_id=123 attempt=2 job=sync\n")
+}
+
+func BenchmarkWriteErrorAttrs(b *testing.B) {
+	ctx := b.Context()
+	ctx = WithAttrs(ctx, "request_id", "123")
+	ctx = WithAttrs(ctx, "job", "sync")
+	err := errors.New("error")
+	for b.Loop() {
+		_ = writeError(ctx, io.Discard, err)
+	}
+}
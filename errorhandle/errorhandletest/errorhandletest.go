@@ -0,0 +1,146 @@
+// Package errorhandletest provides utilities to test code that handles errors with [errorhandle].
+package errorhandletest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/pierrre/go-libs/errorhandle"
+	"github.com/pierrre/go-libs/runtimeutil"
+)
+
+// Record is an error recorded by a [Recorder].
+type Record struct {
+	Context context.Context //nolint:containedctx // It is the context passed to the handler.
+	Err     error
+	Callers []uintptr
+}
+
+// Recorder records the errors handled with [errorhandle.Handle].
+//
+// The recorded errors must be consumed by the test, with [Recorder.Take] or [Recorder.RequireIs].
+// Otherwise, the test fails at cleanup.
+//
+// It is safe for concurrent use.
+type Recorder struct {
+	tb      testing.TB
+	mu      sync.Mutex
+	records []Record
+	changed chan struct{}
+}
+
+// NewRecorder creates a new [Recorder].
+//
+// It returns a [context.Context] derived from ctx, that uses the [Recorder] as [errorhandle.Handler].
+func NewRecorder(ctx context.Context, tb testing.TB) (context.Context, *Recorder) {
+	tb.Helper()
+	r := &Recorder{
+		tb:      tb,
+		changed: make(chan struct{}),
+	}
+	tb.Cleanup(r.cleanup)
+	ctx = errorhandle.SetHandlerToContext(ctx, r.Handle)
+	return ctx, r
+}
+
+// Handle records an error.
+//
+// It implements [errorhandle.Handler].
+func (r *Recorder) Handle(ctx context.Context, err error) {
+	rec := Record{
+		Context: ctx,
+		Err:     err,
+		Callers: runtimeutil.GetCallers(1),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+	close(r.changed) // Notify WaitFor.
+	r.changed = make(chan struct{})
+}
+
+// Len returns the number of recorded errors.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records)
+}
+
+// Take returns the recorded errors, and removes them from the [Recorder].
+func (r *Recorder) Take() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := r.records
+	r.records = nil
+	return records
+}
+
+// WaitFor waits until at least n errors are recorded.
+//
+// If the [context.Context] is done before, it returns the context error.
+func (r *Recorder) WaitFor(ctx context.Context, n int) error {
+	for {
+		r.mu.Lock()
+		l := len(r.records)
+		changed := r.changed
+		r.mu.Unlock()
+		if l >= n {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return context.Cause(ctx) //nolint:wrapcheck // We want to return the original context error.
+		}
+	}
+}
+
+// RequireNone fails the test if there is any recorded error.
+func (r *Recorder) RequireNone(tb testing.TB) {
+	tb.Helper()
+	records := r.Take()
+	if len(records) > 0 {
+		tb.Fatalf("errorhandletest: %d unexpected handled error(s):\n%s", len(records), formatRecords(records))
+	}
+}
+
+// RequireIs fails the test if no recorded error matches target with [errors.Is].
+//
+// The first matching error is removed from the [Recorder] and returned.
+func (r *Recorder) RequireIs(tb testing.TB, target error) Record {
+	tb.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.records, func(rec Record) bool {
+		return errors.Is(rec.Err, target)
+	})
+	if i < 0 {
+		tb.Fatalf("errorhandletest: no handled error matches %q, got %d error(s):\n%s", target, len(r.records), formatRecords(r.records))
+		return Record{}
+	}
+	rec := r.records[i]
+	r.records = slices.Delete(r.records, i, i+1)
+	return rec
+}
+
+func (r *Recorder) cleanup() {
+	r.tb.Helper()
+	records := r.Take()
+	if len(records) > 0 {
+		r.tb.Errorf("errorhandletest: %d handled error(s) not consumed by the test:\n%s", len(records), formatRecords(records))
+	}
+}
+
+func formatRecords(records []Record) string {
+	var b []byte
+	for _, rec := range records {
+		b = append(b, "- "...)
+		b = append(b, rec.Err.Error()...)
+		b = append(b, '\n')
+		b = runtimeutil.AppendCallersFrames(b, rec.Callers)
+	}
+	return string(b)
+}
@@ -0,0 +1,140 @@
+package errorhandletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/errorhandle"
+)
+
+type testTB struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+	fatals   []string
+}
+
+func (tb *testTB) Helper() {}
+
+func (tb *testTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *testTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *testTB) Fatalf(format string, args ...any) {
+	tb.fatals = append(tb.fatals, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+func (tb *testTB) runCleanups() {
+	for _, f := range tb.cleanups {
+		f()
+	}
+}
+
+// runFatal runs f in a new goroutine, because [testTB.Fatalf] calls [runtime.Goexit].
+func runFatal(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	<-done
+}
+
+var errTest = errors.New("test")
+
+func TestRecorder(t *testing.T) {
+	ctx, r := NewRecorder(t.Context(), t)
+	errorhandle.Handle(ctx, fmt.Errorf("wrapped: %w", errTest))
+	errorhandle.Handle(ctx, errors.New("other"))
+	assert.Equal(t, r.Len(), 2)
+	rec := r.RequireIs(t, errTest)
+	assert.ErrorIs(t, rec.Err, errTest)
+	assert.Equal(t, rec.Context, ctx)
+	frame, _ := runtime.CallersFrames(rec.Callers).Next()
+	assert.Equal(t, frame.Function, "github.com/pierrre/go-libs/errorhandle.Handle")
+	recs := r.Take()
+	assert.SliceLen(t, recs, 1)
+	assert.Equal(t, recs[0].Err.Error(), "other")
+	r.RequireNone(t)
+}
+
+func TestRecorderRequireNoneFail(t *testing.T) {
+	tb := new(testTB)
+	ctx, r := NewRecorder(t.Context(), tb)
+	errorhandle.Handle(ctx, errTest)
+	runFatal(func() {
+		r.RequireNone(tb)
+	})
+	assert.SliceLen(t, tb.fatals, 1)
+	assert.True(t, strings.Contains(tb.fatals[0], "1 unexpected handled error(s)"))
+	tb.runCleanups()
+	assert.SliceEmpty(t, tb.errors)
+}
+
+func TestRecorderRequireIsFail(t *testing.T) {
+	tb := new(testTB)
+	ctx, r := NewRecorder(t.Context(), tb)
+	errorhandle.Handle(ctx, errors.New("other"))
+	runFatal(func() {
+		r.RequireIs(tb, errTest)
+	})
+	assert.SliceLen(t, tb.fatals, 1)
+	assert.True(t, strings.Contains(tb.fatals[0], `no handled error matches "test"`))
+	tb.runCleanups()
+	assert.SliceLen(t, tb.errors, 1)
+}
+
+func TestRecorderCleanup(t *testing.T) {
+	tb := new(testTB)
+	ctx, _ := NewRecorder(t.Context(), tb)
+	errorhandle.Handle(ctx, errTest)
+	tb.runCleanups()
+	assert.SliceLen(t, tb.errors, 1)
+	assert.True(t, strings.Contains(tb.errors[0], "1 handled error(s) not consumed by the test"))
+	assert.True(t, strings.Contains(tb.errors[0], "- test\n"))
+}
+
+func TestRecorderWaitFor(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, r := NewRecorder(t.Context(), t)
+		go func() {
+			for range 3 {
+				time.Sleep(time.Second)
+				errorhandle.Handle(ctx, errTest)
+			}
+		}()
+		err := r.WaitFor(ctx, 3)
+		assert.NoError(t, err)
+		assert.SliceLen(t, r.Take(), 3)
+	})
+}
+
+func TestRecorderWaitForContextCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, r := NewRecorder(t.Context(), t)
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		err := r.WaitFor(ctx, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func BenchmarkRecorder(b *testing.B) {
+	ctx, r := NewRecorder(b.Context(), b)
+	for b.Loop() {
+		errorhandle.Handle(ctx, errTest)
+		r.Take()
+	}
+}
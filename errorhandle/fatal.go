@@ -0,0 +1,145 @@
+package errorhandle
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pierrre/go-libs/goroutine"
+	"github.com/pierrre/go-libs/panicutil"
+	"github.com/pierrre/go-libs/syncutil/atomicutil"
+)
+
+// FatalExit is the function called by [Fatal] to exit the process.
+//
+// By default it uses [os.Exit].
+// It can be replaced in tests.
+var FatalExit atomicutil.Value[func(code int)]
+
+// FatalExitCode is the exit code used by [Fatal].
+//
+// By default it is 1.
+var FatalExitCode atomicutil.Value[int]
+
+// FatalTimeout is the maximum duration of the shutdown hooks run by [Fatal].
+//
+// By default it is 10 seconds.
+var FatalTimeout atomicutil.Value[time.Duration]
+
+func init() {
+	FatalExit.Store(os.Exit)
+	FatalExitCode.Store(1)
+	FatalTimeout.Store(10 * time.Second)
+}
+
+// ShutdownHook is a function called by [Fatal] before exiting.
+//
+// It should stop when the [context.Context] is done.
+// The returned error is handled with [Handle].
+type ShutdownHook func(ctx context.Context) error
+
+var shutdownHooks struct {
+	mu      sync.Mutex
+	hooks   []*ShutdownHook
+	running chan struct{} // Closed when the running hooks are finished, nil if no hooks are running.
+}
+
+// AddShutdownHook registers a [ShutdownHook].
+//
+// The hooks are called in the reverse order of registration.
+// The returned function unregisters the hook.
+func AddShutdownHook(h ShutdownHook) (remove func()) {
+	p := &h
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+	shutdownHooks.hooks = append(shutdownHooks.hooks, p)
+	return func() {
+		shutdownHooks.mu.Lock()
+		defer shutdownHooks.mu.Unlock()
+		shutdownHooks.hooks = slices.DeleteFunc(shutdownHooks.hooks, func(hp *ShutdownHook) bool {
+			return hp == p
+		})
+	}
+}
+
+// Fatal handles the error with [Handle], runs the registered shutdown hooks, and exits the process.
+//
+// The hooks are called in LIFO order, with a [context.Context] that is done after [FatalTimeout].
+// If they don't return in time, the process exits anyway.
+// If it is called concurrently, the following calls wait until the hooks that are already running are finished.
+// The process exits with [FatalExit] and [FatalExitCode].
+func Fatal(ctx context.Context, err error) {
+	Handle(ctx, err)
+	runShutdownHooks(ctx)
+	FatalExit.Load()(FatalExitCode.Load())
+}
+
+func runShutdownHooks(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), FatalTimeout.Load()) //nolint:govet // Shadowing is expected here.
+	defer cancel()
+	running, w := startShutdownHooks(ctx)
+	if running == nil {
+		return
+	}
+	select {
+	case <-running:
+		if w != nil {
+			w.Wait()
+		}
+	case <-ctx.Done(): // The hooks didn't return in time, the process exits anyway.
+	}
+}
+
+// startShutdownHooks starts the registered hooks.
+//
+// It returns a channel that is closed when all the running hooks are finished, or nil if no hooks are running.
+// The returned [goroutine.Waiter] is nil if no hooks were registered.
+func startShutdownHooks(ctx context.Context) (<-chan struct{}, goroutine.Waiter) {
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+	hooks := shutdownHooks.hooks
+	shutdownHooks.hooks = nil // The hooks are called only once.
+	previous := shutdownHooks.running
+	if len(hooks) == 0 {
+		return previous, nil // Wait for the hooks started by a concurrent call.
+	}
+	done := make(chan struct{})
+	shutdownHooks.running = done
+	w := goroutine.Start(ctx, func(ctx context.Context) {
+		defer finishShutdownHooks(done)
+		for _, h := range slices.Backward(hooks) {
+			runShutdownHook(ctx, *h)
+		}
+		if previous != nil { // The following calls must also wait for the previous hooks.
+			select {
+			case <-previous:
+			case <-ctx.Done():
+			}
+		}
+	})
+	return done, w
+}
+
+func finishShutdownHooks(done chan struct{}) {
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+	close(done)
+	if shutdownHooks.running == done {
+		shutdownHooks.running = nil
+	}
+}
+
+func runShutdownHook(ctx context.Context, h ShutdownHook) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			Handle(ctx, panicutil.NewError(r))
+		}
+	}()
+	err := h(ctx)
+	if err != nil {
+		Handle(ctx, err)
+	}
+}
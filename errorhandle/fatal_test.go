@@ -0,0 +1,163 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+)
+
+func ExampleFatal() {
+	oldExit := FatalExit.Swap(func(code int) { // Don't exit in the example.
+		fmt.Println("Exit:", code)
+	})
+	defer FatalExit.Store(oldExit)
+	ctx := context.Background()
+	ctx = SetHandlerToContext(ctx, func(ctx context.Context, err error) {
+		fmt.Println("Error:", err)
+	})
+	AddShutdownHook(func(ctx context.Context) error {
+		fmt.Println("Hook 1")
+		return nil
+	})
+	AddShutdownHook(func(ctx context.Context) error {
+		fmt.Println("Hook 2")
+		return nil
+	})
+	Fatal(ctx, errors.New("test"))
+	// Output:
+	// Error: test
+	// Hook 2
+	// Hook 1
+	// Exit: 1
+}
+
+func setTestFatalExit(tb testing.TB) *[]int {
+	tb.Helper()
+	var codes []int
+	old := FatalExit.Swap(func(code int) {
+		codes = append(codes, code)
+	})
+	tb.Cleanup(func() {
+		FatalExit.Store(old)
+	})
+	return &codes
+}
+
+func TestFatal(t *testing.T) {
+	codes := setTestFatalExit(t)
+	oldCode := FatalExitCode.Swap(2)
+	defer FatalExitCode.Store(oldCode)
+	ctx := t.Context()
+	var errs []error
+	ctx = SetHandlerToContext(ctx, func(ctx context.Context, err error) {
+		errs = append(errs, err)
+	})
+	var calls []int
+	errHook := errors.New("hook")
+	AddShutdownHook(func(ctx context.Context) error {
+		calls = append(calls, 1)
+		return nil
+	})
+	remove := AddShutdownHook(func(ctx context.Context) error {
+		calls = append(calls, 2)
+		return nil
+	})
+	AddShutdownHook(func(ctx context.Context) error {
+		calls = append(calls, 3)
+		return errHook
+	})
+	AddShutdownHook(func(ctx context.Context) error {
+		calls = append(calls, 4)
+		panic("panic")
+	})
+	remove()
+	errTest := errors.New("test")
+	Fatal(ctx, errTest)
+	assert.SliceEqual(t, calls, []int{4, 3, 1})
+	assert.SliceLen(t, errs, 3)
+	assert.ErrorIs(t, errs[0], errTest)
+	assert.Equal(t, errs[1].Error()[:len("panic")], "panic")
+	assert.ErrorIs(t, errs[2], errHook)
+	assert.SliceEqual(t, *codes, []int{2})
+	Fatal(ctx, errTest)
+	assert.SliceEqual(t, calls, []int{4, 3, 1}) // The hooks are called only once.
+	assert.SliceEqual(t, *codes, []int{2, 2})
+}
+
+func TestFatalTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		codes := setTestFatalExit(t)
+		ctx := t.Context()
+		ctx = SetHandlerToContext(ctx, func(ctx context.Context, err error) {})
+		unblock := make(chan struct{})
+		AddShutdownHook(func(ctx context.Context) error {
+			<-unblock // Ignore the context.
+			return nil
+		})
+		start := time.Now()
+		Fatal(ctx, errors.New("test"))
+		assert.Equal(t, time.Since(start), 10*time.Second)
+		assert.SliceEqual(t, *codes, []int{1})
+		close(unblock)
+	})
+}
+
+func TestFatalConcurrent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var mu sync.Mutex
+		var events []string
+		addEvent := func(e string) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}
+		old := FatalExit.Swap(func(code int) {
+			addEvent("exit")
+		})
+		defer FatalExit.Store(old)
+		ctx := t.Context()
+		ctx = SetHandlerToContext(ctx, func(ctx context.Context, err error) {})
+		unblock := make(chan struct{})
+		AddShutdownHook(func(ctx context.Context) error {
+			<-unblock
+			addEvent("hook")
+			return nil
+		})
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			Fatal(ctx, errors.New("first"))
+		})
+		synctest.Wait()
+		wg.Go(func() {
+			Fatal(ctx, errors.New("second"))
+		})
+		synctest.Wait()
+		mu.Lock()
+		assert.SliceEmpty(t, events) // The second call waits for the running hooks.
+		mu.Unlock()
+		close(unblock)
+		wg.Wait()
+		assert.SliceEqual(t, events, []string{"hook", "exit", "exit"})
+	})
+}
+
+func TestFatalContextCanceled(t *testing.T) {
+	codes := setTestFatalExit(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	ctx = SetHandlerToContext(ctx, func(ctx context.Context, err error) {})
+	var hookCtxErr error
+	AddShutdownHook(func(ctx context.Context) error {
+		hookCtxErr = ctx.Err()
+		return nil
+	})
+	Fatal(ctx, errors.New("test"))
+	assert.NoError(t, hookCtxErr)
+	assert.SliceEqual(t, *codes, []int{1})
+}
@@ -0,0 +1,204 @@
+package errorhandle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pierrre/go-libs/bytesutil"
+	"github.com/pierrre/go-libs/runtimeutil"
+)
+
+// RecentHandler is a [Handler] that keeps the recent errors in memory.
+//
+// The errors with the same message are grouped, and counted.
+// When the limit is reached, the oldest error is removed.
+//
+// It implements [http.Handler], and renders the recent errors as text, or as JSON with the "format=json" query parameter.
+// It is suitable for mounting next to [net/http/pprof], e.g. on "/debug/errors".
+//
+// It is safe for concurrent use.
+// The zero value is ready to use.
+type RecentHandler struct {
+	// Size is the maximum number of errors.
+	// The default value is 100.
+	Size int
+
+	mu      sync.Mutex
+	entries map[string]*recentEntry // By message.
+	oldest  *recentEntry
+	newest  *recentEntry
+}
+
+// recentEntry is a node of a doubly linked list, from the oldest to the most recent.
+type recentEntry struct {
+	older   *recentEntry
+	newer   *recentEntry
+	first   time.Time
+	last    time.Time
+	message string
+	count   int
+	callers []uintptr
+}
+
+const recentDefaultSize = 100
+
+// Handle records the error.
+func (h *RecentHandler) Handle(ctx context.Context, err error) {
+	now := time.Now()
+	msg := err.Error()
+	callers := getStackFrames(err)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.entries[msg]
+	if ok {
+		h.unlink(e) // It is moved to the most recent position.
+	} else {
+		if h.entries == nil {
+			h.entries = make(map[string]*recentEntry)
+		}
+		if len(h.entries) >= h.getSize() {
+			oldest := h.oldest
+			h.unlink(oldest)
+			delete(h.entries, oldest.message)
+		}
+		e = &recentEntry{
+			first:   now,
+			message: msg,
+		}
+		h.entries[msg] = e
+	}
+	e.last = now
+	e.count++
+	if callers != nil {
+		e.callers = callers
+	}
+	h.pushNewest(e)
+}
+
+func (h *RecentHandler) unlink(e *recentEntry) {
+	if e.older != nil {
+		e.older.newer = e.newer
+	} else {
+		h.oldest = e.newer
+	}
+	if e.newer != nil {
+		e.newer.older = e.older
+	} else {
+		h.newest = e.older
+	}
+	e.older = nil
+	e.newer = nil
+}
+
+func (h *RecentHandler) pushNewest(e *recentEntry) {
+	e.older = h.newest
+	if h.newest != nil {
+		h.newest.newer = e
+	} else {
+		h.oldest = e
+	}
+	h.newest = e
+}
+
+func (h *RecentHandler) getSize() int {
+	if h.Size > 0 {
+		return h.Size
+	}
+	return recentDefaultSize
+}
+
+// RecentError is an error recorded by [RecentHandler].
+type RecentError struct {
+	// First is the time of the first occurrence.
+	First time.Time
+	// Last is the time of the last occurrence.
+	Last time.Time
+	// Message is the error message.
+	Message string
+	// Count is the number of occurrences.
+	Count int
+	// Callers are the stack frames of the last occurrence that implements StackFrames() []uintptr, e.g. [github.com/pierrre/go-libs/panicutil.Error].
+	Callers []uintptr
+}
+
+// Errors returns the recent errors, from the most recent to the oldest.
+func (h *RecentHandler) Errors() []RecentError {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := make([]RecentError, 0, len(h.entries))
+	for e := h.newest; e != nil; e = e.older {
+		res = append(res, RecentError{
+			First:   e.first,
+			Last:    e.last,
+			Message: e.message,
+			Count:   e.count,
+			Callers: e.callers,
+		})
+	}
+	return res
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *RecentHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	errs := h.Errors()
+	w.Header().Set("Cache-Control", "no-cache")
+	if req.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(newRecentErrorsJSON(errs))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	bw := bytesWriterPool.Get()
+	defer bytesWriterPool.Put(bw)
+	*bw = appendRecentErrorsText(*bw, errs)
+	_, _ = w.Write(*bw)
+}
+
+func appendRecentErrorsText(dst []byte, errs []RecentError) []byte {
+	for _, re := range errs {
+		dst = re.Last.AppendFormat(dst, time.RFC3339Nano)
+		dst = append(dst, " (count "...)
+		dst = strconv.AppendInt(dst, int64(re.Count), 10)
+		dst = append(dst, ", first "...)
+		dst = re.First.AppendFormat(dst, time.RFC3339Nano)
+		dst = append(dst, ")\n"...)
+		dst = bytesutil.AppendIndentString(dst, re.Message, 1)
+		dst = append(dst, '\n')
+		if re.Callers != nil {
+			dst = append(dst, "\tstack:\n"...)
+			for f := range runtimeutil.GetCallersFrames(re.Callers) {
+				dst = bytesutil.AppendIndent(dst, runtimeutil.AppendFrame(nil, f), 2)
+			}
+		}
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+type recentErrorJSON struct {
+	First   time.Time    `json:"first"`
+	Last    time.Time    `json:"last"`
+	Message string       `json:"message"`
+	Count   int          `json:"count"`
+	Stack   []stackFrame `json:"stack,omitempty"`
+}
+
+func newRecentErrorsJSON(errs []RecentError) []recentErrorJSON {
+	res := make([]recentErrorJSON, len(errs))
+	for i, re := range errs {
+		res[i] = recentErrorJSON{
+			First:   re.First,
+			Last:    re.Last,
+			Message: re.Message,
+			Count:   re.Count,
+		}
+		if re.Callers != nil {
+			res[i].Stack = getStackFramesJSON(re.Callers)
+		}
+	}
+	return res
+}
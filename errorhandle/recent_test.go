@@ -0,0 +1,103 @@
+package errorhandle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/panicutil"
+)
+
+func TestRecentHandler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		start := time.Now()
+		h := &RecentHandler{
+			Size: 3,
+		}
+		h.Handle(ctx, errors.New("a"))
+		time.Sleep(time.Second)
+		h.Handle(ctx, errors.New("b"))
+		time.Sleep(time.Second)
+		h.Handle(ctx, errors.New("a"))
+		errs := h.Errors()
+		assert.DeepEqual(t, errs, []RecentError{
+			{First: start, Last: start.Add(2 * time.Second), Message: "a", Count: 2},
+			{First: start.Add(time.Second), Last: start.Add(time.Second), Message: "b", Count: 1},
+		})
+		for i := range 3 {
+			h.Handle(ctx, fmt.Errorf("c%d", i))
+		}
+		errs = h.Errors()
+		assert.SliceLen(t, errs, 3)
+		for i, re := range errs {
+			assert.Equal(t, re.Message, fmt.Sprintf("c%d", 2-i))
+		}
+	})
+}
+
+func TestRecentHandlerStack(t *testing.T) {
+	ctx := t.Context()
+	h := new(RecentHandler)
+	h.Handle(ctx, panicutil.NewError("test"))
+	errs := h.Errors()
+	assert.SliceLen(t, errs, 1)
+	assert.SliceNotEmpty(t, errs[0].Callers)
+}
+
+func TestRecentHandlerServeHTTPText(t *testing.T) {
+	ctx := t.Context()
+	h := new(RecentHandler)
+	h.Handle(ctx, errors.New("a"))
+	h.Handle(ctx, fmt.Errorf("b: %w", panicutil.NewError("test")))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/debug/errors", http.NoBody)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "(count 1, first "))
+	assert.True(t, strings.Contains(body, "\ta\n"))
+	assert.True(t, strings.Contains(body, "\tb: test\n"))
+	assert.True(t, strings.Contains(body, "\tstack:\n\t\tgithub.com/pierrre/go-libs/errorhandle.TestRecentHandlerServeHTTPText\n"))
+}
+
+func TestRecentHandlerServeHTTPJSON(t *testing.T) {
+	ctx := t.Context()
+	h := new(RecentHandler)
+	h.Handle(ctx, errors.New("a"))
+	h.Handle(ctx, panicutil.NewError("test"))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/debug/errors?format=json", http.NoBody)
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Header().Get("Content-Type"), "application/json")
+	var res []recentErrorJSON
+	err := json.Unmarshal(w.Body.Bytes(), &res)
+	assert.NoError(t, err)
+	assert.SliceLen(t, res, 2)
+	assert.SliceNotEmpty(t, res[0].Stack)
+	assert.Equal(t, res[0].Stack[0].Function, "github.com/pierrre/go-libs/errorhandle.TestRecentHandlerServeHTTPJSON")
+	assert.Equal(t, res[1].Message, "a")
+	assert.Equal(t, res[1].Count, 1)
+	assert.SliceEmpty(t, res[1].Stack)
+}
+
+func BenchmarkRecentHandler(b *testing.B) {
+	ctx := b.Context()
+	h := new(RecentHandler)
+	for i := range 100 {
+		h.Handle(ctx, fmt.Errorf("error %d", i))
+	}
+	err := errors.New("error 50")
+	for b.Loop() {
+		h.Handle(ctx, err)
+	}
+}
@@ -0,0 +1,60 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// SeverityError is an error with a severity.
+type SeverityError struct {
+	Err      error
+	Severity slog.Level
+}
+
+// WithSeverity returns an error with a severity.
+//
+// [SlogHandler] uses it as log level.
+// It returns nil if err is nil.
+func WithSeverity(err error, severity slog.Level) error {
+	if err == nil {
+		return nil
+	}
+	return &SeverityError{
+		Err:      err,
+		Severity: severity,
+	}
+}
+
+func (err *SeverityError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *SeverityError) Unwrap() error {
+	return err.Err
+}
+
+// GetSeverity returns the severity set with [WithSeverity].
+//
+// It returns false if there is no severity.
+func GetSeverity(err error) (slog.Level, bool) {
+	sevErr, ok := errors.AsType[*SeverityError](err)
+	if !ok {
+		return 0, false
+	}
+	return sevErr.Severity, true
+}
+
+// SeverityFilter returns a filter that returns true if the severity of the error is greater than or equal to minimum.
+//
+// The errors without severity have the severity def.
+// It can be used with [FilterHandler].
+func SeverityFilter(minimum, def slog.Level) func(ctx context.Context, err error) bool {
+	return func(ctx context.Context, err error) bool {
+		severity, ok := GetSeverity(err)
+		if !ok {
+			severity = def
+		}
+		return severity >= minimum
+	}
+}
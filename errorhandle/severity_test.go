@@ -0,0 +1,47 @@
+package errorhandle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/pierrre/assert"
+)
+
+func TestWithSeverity(t *testing.T) {
+	errTest := errors.New("test")
+	err := WithSeverity(errTest, slog.LevelWarn)
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, err.Error(), "test")
+	severity, ok := GetSeverity(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, severity, slog.LevelWarn)
+	assert.NoError(t, WithSeverity(nil, slog.LevelWarn))
+}
+
+func TestGetSeverityNotSet(t *testing.T) {
+	_, ok := GetSeverity(errors.New("test"))
+	assert.False(t, ok)
+}
+
+func TestSeverityFilter(t *testing.T) {
+	ctx := t.Context()
+	f := SeverityFilter(slog.LevelWarn, slog.LevelError)
+	assert.True(t, f(ctx, errors.New("test")))
+	assert.True(t, f(ctx, WithSeverity(errors.New("test"), slog.LevelWarn)))
+	assert.False(t, f(ctx, WithSeverity(errors.New("test"), slog.LevelInfo)))
+}
+
+func TestSlogHandlerSeverity(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelError)
+	h(ctx, WithSeverity(errors.New("test"), slog.LevelWarn))
+	rec := decodeTestSlogRecord(t, buf)
+	assert.Equal(t, rec["level"], any("WARN"))
+	buf.Reset()
+	h(ctx, WithSeverity(errors.New("test"), slog.LevelDebug))
+	assert.Equal(t, buf.Len(), 0)
+}
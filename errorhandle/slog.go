@@ -0,0 +1,98 @@
+package errorhandle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+
+	"github.com/pierrre/go-libs/runtimeutil"
+)
+
+// SlogHandler returns a [Handler] that logs the error with a [slog.Logger].
+//
+// The message is the error message.
+// The level is the severity of the error (see [WithSeverity]), or the given level by default.
+// The following attributes are added:
+//   - the attributes from the [context.Context] (see [WithAttrs])
+//   - "stack": the stack frames of the first error in the tree that implements StackFrames() []uintptr (e.g. [github.com/pierrre/go-libs/panicutil.Error])
+//   - "errors": the messages of the leaf errors of the first error in the tree created with [errors.Join]
+func SlogHandler(logger *slog.Logger, level slog.Level) Handler {
+	return func(ctx context.Context, err error) {
+		lvl := level
+		if severity, ok := GetSeverity(err); ok {
+			lvl = severity
+		}
+		if !logger.Enabled(ctx, lvl) {
+			return
+		}
+		attrs := appendAttrsFromContext(ctx, make([]slog.Attr, 0, 8))
+		if sf := getStackFrames(err); sf != nil {
+			attrs = append(attrs, slog.Any("stack", slogStack(sf)))
+		}
+		if joinErr, ok := errors.AsType[joinError](err); ok {
+			attrs = append(attrs, slog.Any("errors", flattenErrors(nil, joinErr)))
+		}
+		logger.LogAttrs(ctx, lvl, err.Error(), attrs...)
+	}
+}
+
+type stackFramesError interface {
+	error
+	StackFrames() []uintptr
+}
+
+func getStackFrames(err error) []uintptr {
+	sfErr, ok := errors.AsType[stackFramesError](err)
+	if !ok {
+		return nil
+	}
+	return sfErr.StackFrames()
+}
+
+type joinError interface {
+	error
+	Unwrap() []error
+}
+
+// flattenErrors appends the messages of the leaf errors of a tree created with [errors.Join].
+func flattenErrors(dst []string, err error) []string {
+	if err == nil {
+		return dst
+	}
+	if u, ok := err.(joinError); ok { //nolint:errorlint // We want to check the error itself.
+		for _, e := range u.Unwrap() {
+			dst = flattenErrors(dst, e)
+		}
+		return dst
+	}
+	return append(dst, err.Error())
+}
+
+type slogStack []uintptr
+
+func (s slogStack) LogValue() slog.Value {
+	return slog.AnyValue(getStackFramesJSON(s))
+}
+
+func getStackFramesJSON(callers []uintptr) []stackFrame {
+	var frames []stackFrame
+	for f := range runtimeutil.GetCallersFrames(callers) {
+		frames = append(frames, newStackFrame(f))
+	}
+	return frames
+}
+
+type stackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func newStackFrame(f runtime.Frame) stackFrame { //nolint:gocritic // runtime.Frame is large.
+	return stackFrame{
+		Function: f.Function,
+		File:     f.File,
+		Line:     f.Line,
+	}
+}
@@ -0,0 +1,125 @@
+package errorhandle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/panicutil"
+)
+
+func ExampleSlogHandler() {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	h := SlogHandler(logger, slog.LevelError)
+	ctx = WithAttrs(ctx, "request_id", "123")
+	h(ctx, errors.New("test"))
+	// Output: level=ERROR msg=test request_id=123
+}
+
+func newTestSlogHandler(buf *bytes.Buffer, level slog.Level) Handler {
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	return SlogHandler(logger, level)
+}
+
+func decodeTestSlogRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var rec map[string]any
+	err := json.Unmarshal(buf.Bytes(), &rec)
+	assert.NoError(t, err)
+	return rec
+}
+
+func TestSlogHandler(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelError)
+	ctx = WithAttrs(ctx, "a", "1")
+	ctx = WithAttrs(ctx, "b", 2)
+	h(ctx, errors.New("test"))
+	rec := decodeTestSlogRecord(t, buf)
+	assert.Equal(t, rec["level"], any("ERROR"))
+	assert.Equal(t, rec["msg"], any("test"))
+	assert.Equal(t, rec["a"], any("1"))
+	assert.Equal(t, rec["b"], any(float64(2)))
+	_, ok := rec["stack"]
+	assert.False(t, ok)
+	_, ok = rec["errors"]
+	assert.False(t, ok)
+}
+
+func TestSlogHandlerDisabled(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelDebug)
+	h(ctx, errors.New("test"))
+	assert.Equal(t, buf.Len(), 0)
+}
+
+func TestSlogHandlerStack(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelError)
+	err := panicutil.NewError("test")
+	h(ctx, errors.Join(errors.New("other"), err))
+	rec := decodeTestSlogRecord(t, buf)
+	stack, ok := rec["stack"].([]any)
+	assert.True(t, ok)
+	assert.SliceNotEmpty(t, stack)
+	frame, ok := stack[0].(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, frame["function"], any("github.com/pierrre/go-libs/errorhandle.TestSlogHandlerStack"))
+	assert.NotZero(t, frame["file"])
+	assert.NotZero(t, frame["line"])
+}
+
+func TestSlogHandlerJoin(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelError)
+	err := errors.Join(
+		errors.New("a"),
+		errors.Join(errors.New("b"), errors.New("c")),
+		nil,
+	)
+	h(ctx, err)
+	rec := decodeTestSlogRecord(t, buf)
+	assert.DeepEqual(t, rec["errors"], any([]any{"a", "b", "c"}))
+}
+
+func TestSlogHandlerJoinWrapped(t *testing.T) {
+	ctx := t.Context()
+	buf := new(bytes.Buffer)
+	h := newTestSlogHandler(buf, slog.LevelError)
+	err := fmt.Errorf("wrapped: %w", errors.Join(errors.New("a"), errors.New("b")))
+	h(ctx, err)
+	rec := decodeTestSlogRecord(t, buf)
+	assert.DeepEqual(t, rec["errors"], any([]any{"a", "b"}))
+}
+
+func BenchmarkSlogHandler(b *testing.B) {
+	ctx := b.Context()
+	ctx = WithAttrs(ctx, "a", "1")
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	h := SlogHandler(logger, slog.LevelError)
+	err := errors.New("error")
+	for b.Loop() {
+		h(ctx, err)
+	}
+}
@@ -0,0 +1,167 @@
+// Package httphandle provides HTTP handler utilities.
+package httphandle
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/pierrre/go-libs/errorhandle"
+	"github.com/pierrre/go-libs/httpurl"
+	"github.com/pierrre/go-libs/panichandle"
+	"github.com/pierrre/go-libs/panicutil"
+)
+
+// Recover returns an [http.Handler] that recovers panics.
+//
+// The recovered value is converted to a [panicutil.Error].
+// It is passed to the [panichandle.Handler] returned by [panichandle.GetHandler], or to [errorhandle.Handle] if there is none.
+// The [context.Context] contains the [http.Request], with the absolute URL returned by [httpurl.Get] (see [GetRequestFromContext]).
+//
+// If the response headers were not sent yet, an error response is written (see [WithResponse]).
+//
+// A panic with [http.ErrAbortHandler] is not recovered, so the server aborts the response.
+func Recover(h http.Handler, opts ...Option) http.Handler {
+	o := buildOptions(opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(r)
+			}
+			handlePanic(rw, req, r, o)
+		}()
+		h.ServeHTTP(rw, req)
+	})
+}
+
+func handlePanic(rw *responseWriter, req *http.Request, r any, o *options) {
+	err := panicutil.NewError(r)
+	ctx := req.Context()
+	ctx = SetRequestToContext(ctx, requestWithURL(req))
+	if ph := panichandle.GetHandler(ctx); ph != nil {
+		ph(ctx, err)
+	} else {
+		errorhandle.Handle(ctx, err)
+	}
+	if !rw.wroteHeader {
+		o.response(rw, req, err)
+	}
+}
+
+// requestWithURL returns a shallow copy of the request with the absolute URL.
+func requestWithURL(req *http.Request) *http.Request {
+	req = req.WithContext(req.Context())
+	req.URL = httpurl.Get(req)
+	return req
+}
+
+// DefaultResponse writes a 500 Internal Server Error response.
+func DefaultResponse(w http.ResponseWriter, req *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+type options struct {
+	response func(w http.ResponseWriter, req *http.Request, err error)
+}
+
+func buildOptions(opts ...Option) *options {
+	o := &options{
+		response: DefaultResponse,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is an option for [Recover].
+type Option func(*options)
+
+// WithResponse sets the function that writes the response after a panic.
+//
+// It is called only if the response headers were not sent yet.
+// The default value is [DefaultResponse].
+func WithResponse(f func(w http.ResponseWriter, req *http.Request, err error)) Option {
+	return func(o *options) {
+		o.response = f
+	}
+}
+
+type contextKey struct{}
+
+// SetRequestToContext sets an [http.Request] to a [context.Context].
+func SetRequestToContext(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+// GetRequestFromContext gets an [http.Request] from a [context.Context].
+//
+// It returns nil if no [http.Request] is set.
+func GetRequestFromContext(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(contextKey{}).(*http.Request)
+	return req
+}
+
+// responseWriter is an [http.ResponseWriter] that tracks whether the headers were sent.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if statusCode >= 200 || statusCode == http.StatusSwitchingProtocols {
+		w.wroteHeader = true // Informational responses don't send the final headers.
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p) //nolint:wrapcheck // Not needed.
+}
+
+// ReadFrom implements [io.ReaderFrom], so the wrapped [http.ResponseWriter] can use sendfile.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wroteHeader = true
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if ok {
+		return rf.ReadFrom(r) //nolint:wrapcheck // Not needed.
+	}
+	return io.Copy(w.ResponseWriter, r) //nolint:wrapcheck // Not needed.
+}
+
+func (w *responseWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError is used by [http.ResponseController.Flush].
+func (w *responseWriter) FlushError() error {
+	err := http.NewResponseController(w.ResponseWriter).Flush()
+	if err != nil {
+		return err //nolint:wrapcheck // Not needed.
+	}
+	w.wroteHeader = true
+	return nil
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck // Not needed.
+	}
+	w.wroteHeader = true // The connection is not managed by the server anymore.
+	return conn, brw, nil
+}
+
+// Unwrap returns the wrapped [http.ResponseWriter], for [http.ResponseController].
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,242 @@
+package httphandle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pierrre/assert"
+	"github.com/pierrre/go-libs/errorhandle"
+	"github.com/pierrre/go-libs/panichandle"
+	"github.com/pierrre/go-libs/panicutil"
+)
+
+func Example() {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("test")
+	}))
+	ctx := context.Background()
+	ctx = errorhandle.SetHandlerToContext(ctx, func(ctx context.Context, err error) {
+		req := GetRequestFromContext(ctx)
+		var panicErr *panicutil.Error
+		_ = errors.As(err, &panicErr)
+		fmt.Println("Panic:", panicErr.Recovered, req.URL)
+	})
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	fmt.Println("Status:", w.Code)
+	// Output:
+	// Panic: test http://example.com/test
+	// Status: 500
+}
+
+func newTestRequest(t *testing.T, h errorhandle.Handler) *http.Request {
+	t.Helper()
+	ctx := t.Context()
+	ctx = errorhandle.SetHandlerToContext(ctx, h)
+	return httptest.NewRequestWithContext(ctx, http.MethodGet, "/test?a=1", http.NoBody)
+}
+
+func TestRecover(t *testing.T) {
+	var handledErr error
+	var handledReq *http.Request
+	req := newTestRequest(t, func(ctx context.Context, err error) {
+		handledErr = err
+		handledReq = GetRequestFromContext(ctx)
+	})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+	assert.Equal(t, w.Body.String(), "Internal Server Error\n")
+	panicErr, _ := assert.Type[*panicutil.Error](t, handledErr)
+	assert.Equal(t, panicErr.Recovered, any("test"))
+	assert.SliceNotEmpty(t, panicErr.StackFrames())
+	assert.Equal(t, handledReq.URL.String(), "http://example.com/test?a=1")
+	assert.Equal(t, req.URL.String(), "/test?a=1") // The original request is not modified.
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {
+		t.Fatal("should not be called")
+	})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusNoContent)
+}
+
+func TestRecoverHeadersSent(t *testing.T) {
+	called := false
+	req := newTestRequest(t, func(ctx context.Context, err error) {
+		called = true
+	})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.True(t, called)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "partial")
+}
+
+func TestRecoverInformationalHeader(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusEarlyHints)
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Body.String(), "Internal Server Error\n") // The recorder keeps the informational status code.
+}
+
+func TestRecoverFlush(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		err := http.NewResponseController(w).Flush()
+		assert.NoError(t, err)
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, w.Code, http.StatusOK)
+}
+
+func TestRecoverFlushNotSupported(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		err := http.NewResponseController(w).Flush()
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+		panic("test")
+	}))
+	rec := httptest.NewRecorder()
+	w := struct{ http.ResponseWriter }{rec} // Hide Flush.
+	h.ServeHTTP(w, req)
+	assert.Equal(t, rec.Code, http.StatusInternalServerError)
+}
+
+type testReaderFromResponseWriter struct {
+	*httptest.ResponseRecorder
+	called bool
+}
+
+func (w *testReaderFromResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.called = true
+	return io.Copy(w.ResponseRecorder, r) //nolint:wrapcheck // Not needed.
+}
+
+func TestRecoverReadFrom(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("test")) //nolint:forcetypeassert // The test checks that it is implemented.
+		assert.NoError(t, err)
+		panic("test")
+	}))
+	w := &testReaderFromResponseWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+	}
+	h.ServeHTTP(w, req)
+	assert.True(t, w.called)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "test")
+}
+
+func TestRecoverReadFromNotSupported(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("test")) //nolint:forcetypeassert // The test checks that it is implemented.
+		assert.NoError(t, err)
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "test")
+}
+
+func TestRecoverHijackNotSupported(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack() //nolint:forcetypeassert // The test checks that it is implemented.
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+}
+
+func TestRecoverResponse(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {})
+	h := Recover(
+		http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			panic("test")
+		}),
+		WithResponse(func(w http.ResponseWriter, req *http.Request, err error) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusServiceUnavailable)
+}
+
+func TestRecoverPanicHandler(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {
+		t.Fatal("should not be called")
+	})
+	var recovered any
+	ctx := panichandle.SetHandlerToContext(req.Context(), func(ctx context.Context, r any) {
+		recovered = r
+	})
+	req = req.WithContext(ctx)
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("test")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+	panicErr, _ := assert.Type[*panicutil.Error](t, recovered)
+	assert.Equal(t, panicErr.Recovered, any("test"))
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	req := newTestRequest(t, func(ctx context.Context, err error) {
+		t.Fatal("should not be called")
+	})
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	w := httptest.NewRecorder()
+	assert.Panics(t, func() {
+		h.ServeHTTP(w, req)
+	})
+}
+
+func TestGetRequestFromContextNotSet(t *testing.T) {
+	ctx := t.Context()
+	assert.True(t, GetRequestFromContext(ctx) == nil)
+}
+
+func BenchmarkRecover(b *testing.B) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	req := httptest.NewRequestWithContext(b.Context(), http.MethodGet, "/test", http.NoBody)
+	w := httptest.NewRecorder()
+	for b.Loop() {
+		h.ServeHTTP(w, req)
+	}
+}